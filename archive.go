@@ -0,0 +1,247 @@
+package script
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ArchiveOptions are options for archiving files.
+type ArchiveOptions struct {
+	// StripPrefix is removed from the beginning of the paths of the files in the archive. Files
+	// which their path is equal to the prefix, such as the listed directory, are omitted from the
+	// archive.
+	StripPrefix string
+}
+
+// Tar outputs a tar archive of the files. The paths of the files in the archive are their paths in
+// the list, without a leading '/'. Directories are added as entries without their content, use
+// `LsRecursive` in order to archive the files inside directories. The archive is created as the
+// stream is read: each file is opened only when the previous file was archived, and its content
+// is not stored in memory.
+//
+// If a file fails to be opened, it results in an error in the output, but the archive will still
+// contain the other files. A failure while reading the content of a file ends the archive with an
+// error, since the archive can't be completed.
+//
+// Shell command: `tar -c --no-recursion <files>`.
+func (f Files) Tar() Stream {
+	return f.TarWith(ArchiveOptions{})
+}
+
+// TarWith outputs a tar archive of the files, similar to `Tar`, according to the given options.
+//
+// Shell command: `tar -c --no-recursion --transform='s|^<prefix>||' <files>`.
+func (f Files) TarWith(opts ArchiveOptions) Stream {
+	a := &archiveReader{files: f.Files, opts: opts}
+	a.w = &tarArchive{tw: tar.NewWriter(&a.buf)}
+	return f.stream("tar", a)
+}
+
+// Zip outputs a zip archive of the files. The file contents are compressed using deflate. The
+// archive is created similar to `Tar`.
+//
+// Shell command: `zip - <files>`.
+func (f Files) Zip() Stream {
+	return f.ZipWith(ArchiveOptions{})
+}
+
+// ZipWith outputs a zip archive of the files, similar to `Zip`, according to the given options.
+func (f Files) ZipWith(opts ArchiveOptions) Stream {
+	a := &archiveReader{files: f.Files, opts: opts}
+	a.w = &zipArchive{zw: zip.NewWriter(&a.buf)}
+	return f.stream("zip", a)
+}
+
+// archiveWriter adds entries to an archive.
+type archiveWriter interface {
+	// create adds an entry with the given name to the archive. For a symbolic link, link is its
+	// target. It returns a writer for the content of the entry.
+	create(name string, info os.FileInfo, link string) (io.Writer, error)
+	// Close writes the end of the archive.
+	Close() error
+}
+
+type tarArchive struct {
+	tw *tar.Writer
+}
+
+func (t *tarArchive) create(name string, info os.FileInfo, link string) (io.Writer, error) {
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return nil, err
+	}
+	hdr.Name = name
+	if err := t.tw.WriteHeader(hdr); err != nil {
+		return nil, err
+	}
+	return t.tw, nil
+}
+
+func (t *tarArchive) Close() error {
+	return t.tw.Close()
+}
+
+type zipArchive struct {
+	zw *zip.Writer
+}
+
+func (z *zipArchive) create(name string, info os.FileInfo, link string) (io.Writer, error) {
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return nil, err
+	}
+	hdr.Name = name
+	if !info.IsDir() {
+		hdr.Method = zip.Deflate
+	}
+	w, err := z.zw.CreateHeader(hdr)
+	if err != nil {
+		return nil, err
+	}
+	// Zip stores the target of a symbolic link as its content.
+	if link != "" {
+		if _, err := io.WriteString(w, link); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (z *zipArchive) Close() error {
+	return z.zw.Close()
+}
+
+// archiveReader archives files into an archive writer, which writes to a buffer that the archive is
+// read from.
+type archiveReader struct {
+	files []FileInfo
+	opts  ArchiveOptions
+	w     archiveWriter
+	// buf stores archived data that was not read yet.
+	buf bytes.Buffer
+	// cur is the file that its content is currently being archived into entry.
+	cur    *os.File
+	entry  io.Writer
+	done   bool
+	errors *multierror.Error
+}
+
+func (a *archiveReader) Read(b []byte) (int, error) {
+	for a.buf.Len() == 0 {
+		if a.done {
+			return 0, io.EOF
+		}
+		if err := a.archiveNext(len(b)); err != nil {
+			a.done = true
+			return 0, err
+		}
+	}
+	return a.buf.Read(b)
+}
+
+// archiveNext archives up to n bytes of the current file, or adds the next file to the archive if
+// there is no current file.
+func (a *archiveReader) archiveNext(n int) error {
+	if a.cur != nil {
+		_, err := io.CopyN(a.entry, a.cur, int64(n))
+		if err == io.EOF {
+			a.closeCurrent()
+			return nil
+		}
+		if err != nil {
+			path := a.cur.Name()
+			a.closeCurrent()
+			return pathError("archive path", path, err)
+		}
+		return nil
+	}
+
+	if len(a.files) == 0 {
+		a.done = true
+		if err := a.w.Close(); err != nil {
+			return fmt.Errorf("close archive: %w", err)
+		}
+		return nil
+	}
+
+	file := a.files[0]
+	a.files = a.files[1:]
+	if err := a.add(file); err != nil {
+		a.errors = multierror.Append(a.errors, pathError("archive path", file.Path, err))
+	}
+	return nil
+}
+
+// add adds an entry for the given file. For a regular file, it opens the file such that its content
+// is archived by the following calls.
+func (a *archiveReader) add(file FileInfo) error {
+	name := a.name(file.Path)
+	if name == "" {
+		return nil
+	}
+
+	mode := file.Mode()
+	switch {
+	case mode.IsDir():
+		_, err := a.w.create(name+"/", file.FileInfo, "")
+		return err
+	case mode&os.ModeSymlink != 0:
+		target, err := file.SymlinkTarget()
+		if err != nil {
+			return err
+		}
+		_, err = a.w.create(name, file.FileInfo, target)
+		return err
+	case mode.IsRegular():
+		f, err := os.Open(file.Path)
+		if err != nil {
+			return err
+		}
+		// Use the current information of the file, since its size might have changed since it was
+		// listed.
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		entry, err := a.w.create(name, info, "")
+		if err != nil {
+			f.Close()
+			return err
+		}
+		a.cur, a.entry = f, entry
+		return nil
+	default:
+		return fmt.Errorf("unsupported file type %s", mode&os.ModeType)
+	}
+}
+
+// name returns the name of the given path in the archive.
+func (a *archiveReader) name(path string) string {
+	name := filepath.ToSlash(strings.TrimPrefix(path, a.opts.StripPrefix))
+	return strings.TrimLeft(name, "/")
+}
+
+// Close closes the currently archived file and returns all the errors of files that failed to be
+// archived.
+func (a *archiveReader) Close() error {
+	if a.cur != nil {
+		a.closeCurrent()
+	}
+	return a.errors.ErrorOrNil()
+}
+
+func (a *archiveReader) closeCurrent() {
+	if err := a.cur.Close(); err != nil {
+		a.errors = multierror.Append(a.errors, pathError("close path", a.cur.Name(), err))
+	}
+	a.cur, a.entry = nil, nil
+}
@@ -0,0 +1,169 @@
+package script
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTar(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+
+	t.Run("tar", func(t *testing.T) {
+		got, err := Ls("testdata").Tar().Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"testdata/a.txt": "a\n",
+			"testdata/b.txt": "bb\n",
+		}, readTar(t, got))
+	})
+
+	t.Run("strip prefix", func(t *testing.T) {
+		got, err := LsRecursive(dir).TarWith(ArchiveOptions{StripPrefix: dir}).Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"a.txt":     filepath.Join(dir, "a.txt") + "\n",
+			"b/c.txt":   filepath.Join(dir, "b/c.txt") + "\n",
+			"b/d/e.txt": filepath.Join(dir, "b/d/e.txt") + "\n",
+		}, readTar(t, got))
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		got, err := Ls(dir).TarWith(ArchiveOptions{StripPrefix: dir}).Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"a.txt": filepath.Join(dir, "a.txt") + "\n", "b/": ""}, readTar(t, got))
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		got, err := Ls("testdata/a.txt").Tar().Gzip().Gunzip().Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"testdata/a.txt": "a\n"}, readTar(t, got))
+	})
+
+	t.Run("symlink", func(t *testing.T) {
+		link := filepath.Join(dir, "link")
+		require.NoError(t, os.Symlink("a.txt", link))
+		defer os.Remove(link)
+
+		files := Ls(dir).Filter(func(f FileInfo) bool { return f.Name() == "link" })
+		got, err := files.TarWith(ArchiveOptions{StripPrefix: dir}).Bytes()
+		require.NoError(t, err)
+		r := tar.NewReader(bytes.NewReader(got))
+		hdr, err := r.Next()
+		require.NoError(t, err)
+		assert.Equal(t, "link", hdr.Name)
+		assert.Equal(t, byte(tar.TypeSymlink), hdr.Typeflag)
+		assert.Equal(t, "a.txt", hdr.Linkname)
+	})
+
+	t.Run("file error", func(t *testing.T) {
+		path := filepath.Join(dir, "removed.txt")
+		require.NoError(t, ioutil.WriteFile(path, []byte("removed"), 0664))
+		files := Ls(path, filepath.Join(dir, "a.txt"))
+		require.NoError(t, os.Remove(path))
+
+		got, err := files.TarWith(ArchiveOptions{StripPrefix: dir}).Bytes()
+		assert.Error(t, err)
+		assert.Equal(t, map[string]string{"a.txt": filepath.Join(dir, "a.txt") + "\n"}, readTar(t, got))
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Ls("no-such-file", "testdata/a.txt").Tar().Bytes()
+		assert.Error(t, err)
+		assert.Equal(t, map[string]string{"testdata/a.txt": "a\n"}, readTar(t, got))
+	})
+}
+
+func TestZip(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+
+	t.Run("zip", func(t *testing.T) {
+		got, err := Ls("testdata").Zip().Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"testdata/a.txt": "a\n",
+			"testdata/b.txt": "bb\n",
+		}, readZip(t, got))
+	})
+
+	t.Run("strip prefix", func(t *testing.T) {
+		got, err := LsRecursive(dir).ZipWith(ArchiveOptions{StripPrefix: dir + "/"}).Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"a.txt":     filepath.Join(dir, "a.txt") + "\n",
+			"b/c.txt":   filepath.Join(dir, "b/c.txt") + "\n",
+			"b/d/e.txt": filepath.Join(dir, "b/d/e.txt") + "\n",
+		}, readZip(t, got))
+	})
+
+	t.Run("large file", func(t *testing.T) {
+		content := strings.Repeat("0123456789\n", 10000)
+		path := tempFile(t, content)
+		defer os.RemoveAll(filepath.Dir(path))
+
+		got, err := Ls(path).ZipWith(ArchiveOptions{StripPrefix: filepath.Dir(path)}).Bytes()
+		require.NoError(t, err)
+		assert.Less(t, len(got), len(content))
+		assert.Equal(t, map[string]string{filepath.Base(path): content}, readZip(t, got))
+	})
+
+	t.Run("file error", func(t *testing.T) {
+		path := filepath.Join(dir, "removed.txt")
+		require.NoError(t, ioutil.WriteFile(path, []byte("removed"), 0664))
+		files := Ls(path, filepath.Join(dir, "a.txt"))
+		require.NoError(t, os.Remove(path))
+
+		got, err := files.ZipWith(ArchiveOptions{StripPrefix: dir}).Bytes()
+		assert.Error(t, err)
+		assert.Equal(t, map[string]string{"a.txt": filepath.Join(dir, "a.txt") + "\n"}, readZip(t, got))
+	})
+}
+
+// readTar returns the contents of the entries of the given tar archive by their names.
+func readTar(t *testing.T, archive []byte) map[string]string {
+	t.Helper()
+	out := make(map[string]string)
+	r := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := r.Next()
+		if err == io.EOF {
+			return out
+		}
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		out[hdr.Name] = string(content)
+	}
+}
+
+// readZip returns the contents of the entries of the given zip archive by their names.
+func readZip(t *testing.T, archive []byte) map[string]string {
+	t.Helper()
+	out := make(map[string]string)
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		out[f.Name] = string(content)
+	}
+	return out
+}
@@ -0,0 +1,27 @@
+package script
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Buffered reads the stream in chunks of the given size, and serves reads of the following stages
+// from the buffered data. It does not change the content of the stream, but improves performance
+// when the following stages perform many small reads from a stage that performs work for each
+// read, for example a consumer that reads a single byte at a time from a file or from a command
+// output, where each read is a system call. Sizes smaller than 16 bytes are
+// increased to 16 bytes, the minimal size of `bufio.Reader`.
+func (s Stream) Buffered(size int) Stream {
+	return s.Through(bufferedPipe(size))
+}
+
+type bufferedPipe int
+
+func (b bufferedPipe) Pipe(stdin io.Reader) (io.Reader, error) {
+	return bufio.NewReaderSize(stdin, int(b)), nil
+}
+
+func (b bufferedPipe) Name() string {
+	return fmt.Sprintf("buffered(%d)", b)
+}
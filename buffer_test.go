@@ -0,0 +1,86 @@
+package script
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuffered(t *testing.T) {
+	t.Parallel()
+
+	t.Run("content", func(t *testing.T) {
+		got, err := numbers(1000).Buffered(64).ToString()
+		require.NoError(t, err)
+		want, err := numbers(1000).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("small reads", func(t *testing.T) {
+		s := Ls("testdata").Buffered(0)
+		got, err := ioutil.ReadAll(iotest.OneByteReader(s))
+		require.NoError(t, err)
+		assert.Equal(t, "testdata/a.txt\ntestdata/b.txt\n", string(got))
+		assert.NoError(t, s.Close())
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Ls("no-such-file", "testdata/a.txt").Buffered(1024).ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "testdata/a.txt\n", got)
+	})
+}
+
+// BenchmarkBuffered compares reading a large recursive listing a single byte at a time, with and
+// without buffering. The listing is read directly, and from a file, where each unbuffered read is
+// a system call.
+func BenchmarkBuffered(b *testing.B) {
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(b, err)
+	defer os.RemoveAll(dir)
+	for i := 0; i < 1000; i++ {
+		path := fmt.Sprintf("%s/%02d/file-%04d.txt", dir, i%10, i)
+		require.NoError(b, makeDir(path))
+		require.NoError(b, ioutil.WriteFile(path, nil, 0664))
+	}
+	files := LsRecursive(dir)
+	require.Len(b, files.Files, 1000)
+	listing := filepath.Join(dir, "listing")
+	_, err = files.WriteFile(listing)
+	require.NoError(b, err)
+
+	sources := []struct {
+		name string
+		s    func() Stream
+	}{
+		{name: "ls", s: func() Stream { return newFiles("ls", files.Files, nil).Stream }},
+		{name: "cat", s: func() Stream { return Cat(listing) }},
+	}
+
+	for _, source := range sources {
+		for _, size := range []int{0, 4096} {
+			b.Run(fmt.Sprintf("%s/size=%d", source.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					s := source.s()
+					if size > 0 {
+						s = s.Buffered(size)
+					}
+					if _, err := io.Copy(ioutil.Discard, iotest.OneByteReader(s)); err != nil {
+						b.Fatal(err)
+					}
+					if err := s.Close(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package script
+
+// Cache returns a stream with the content of the stream, and a function that returns new streams
+// with the same content, such that the content can be processed several times while the stream is
+// read only once. The content is read lazily, when any of the streams is read, and the whole content
+// is kept in memory for the streams that the function returns, until all of them are no longer
+// referenced. The stream is closed once it was fully read. The function can be called at any time
+// and from any goroutine, and each call returns a stream that starts from the beginning.
+//
+// The errors of the stream are part of the errors of all the returned streams, and errors that
+// occur when the stream is closed are returned by the streams that are closed after it was fully
+// read.
+//
+// Usage:
+//
+//  s, again := script.Get(url).Cache()
+//  lines, err := s.CountLines()
+//  sum, err := again().SHA256Sum()
+func (s Stream) Cache() (Stream, func() Stream) {
+	src := &sharedSource{s: s, retain: true}
+	return src.branch("cache"), func() Stream { return src.branch("cache") }
+}
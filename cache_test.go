@@ -0,0 +1,79 @@
+package script
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat("a\nbb\na\n", 1000)
+
+	t.Run("several terminals", func(t *testing.T) {
+		r := &closeCounter{Reader: strings.NewReader(content)}
+		s, again := FromReader(r).Cache()
+		n, err := s.CountLines()
+		require.NoError(t, err)
+		assert.Equal(t, 3000, n)
+
+		freq, err := again().Freq().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2000 a", "1000 bb"}, freq)
+
+		got, err := again().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+		assert.Equal(t, 1, r.closed)
+	})
+
+	t.Run("factory before reading", func(t *testing.T) {
+		r := &closeCounter{Reader: strings.NewReader("a\nb\n")}
+		s, again := FromReader(r).Cache()
+		got, err := again().Head(1).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\n", got)
+		assert.Equal(t, 0, r.closed)
+
+		got, err = s.ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb\n", got)
+		assert.Equal(t, 1, r.closed)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		_, again := Echo(content).Cache()
+		got := make([]string, 4)
+		var wg sync.WaitGroup
+		for i := range got {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				got[i], _ = again().ToString()
+			}(i)
+		}
+		wg.Wait()
+		for _, g := range got {
+			assert.Equal(t, content+"\n", g)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		s, again := Cat("testdata/a.txt", "no-such-file").Cache()
+		got, err := s.ToString()
+		assert.True(t, errors.Is(err, os.ErrNotExist), "%v", err)
+		assert.Equal(t, "a\n", got)
+		got, err = again().ToString()
+		assert.True(t, errors.Is(err, os.ErrNotExist), "%v", err)
+		assert.Equal(t, "a\n", got)
+
+		_, again = Ls("no-such-file").Cache()
+		assert.Error(t, again().Error())
+	})
+}
@@ -0,0 +1,19 @@
+package script
+
+import (
+	"bytes"
+)
+
+// ToUpper converts all the letters in each line to upper case.
+//
+// Shell command: `tr '[:lower:]' '[:upper:]'`.
+func (s Stream) ToUpper() Stream {
+	return s.Modify(mapLines{name: "to-upper", fn: bytes.ToUpper})
+}
+
+// ToLower converts all the letters in each line to lower case.
+//
+// Shell command: `tr '[:upper:]' '[:lower:]'`.
+func (s Stream) ToLower() Stream {
+	return s.Modify(mapLines{name: "to-lower", fn: bytes.ToLower})
+}
@@ -0,0 +1,24 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("upper", func(t *testing.T) {
+		got, err := Echo("Hello\nwörld").ToUpper().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "HELLO\nWÖRLD\n", got)
+	})
+
+	t.Run("lower", func(t *testing.T) {
+		got, err := Echo("Hello\nWÖRLD").ToLower().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "hello\nwörld\n", got)
+	})
+}
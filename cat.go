@@ -4,46 +4,122 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/hashicorp/go-multierror"
 )
 
-// Cat outputs the contents of the given files.
+// Cat outputs the contents of the given files. The files are opened lazily, one after the other,
+// in the order of the arguments. If a file fails to be opened, it will result in an error in the
+// output, but the stream will still contain the content of the other files.
+//
+// If no paths are given, the stream reads from stdin.
 //
 // Shell command: cat <path>.
 func Cat(paths ...string) Stream {
-	var (
-		readers []io.Reader
-		closers multicloser
-		errors  *multierror.Error
-	)
-
-	for _, path := range paths {
-		f, err := os.Open(path)
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("open path %s: %v", path, err))
-		} else {
-			readers = append(readers, f)
-			closers = append(closers, f)
-		}
+	if len(paths) == 0 {
+		s := Stdin()
+		s.stage = "cat"
+		return s
 	}
-
 	return Stream{
-		r:     readcloser{Reader: io.MultiReader(readers...), Closer: closers},
+		r:     &catReader{paths: paths},
 		stage: "cat",
-		err:   errors.ErrorOrNil(),
 	}
+}
+
+// OpenFile outputs the content of a single file. The file is opened when the stream is created,
+// such that a failure to open it is available using the stream's `Error` method. The file is closed
+// once it is fully read, when reading it fails, or when the stream is closed.
+//
+// Shell command: cat <path>.
+func OpenFile(path string) Stream {
+	stage := fmt.Sprintf("open(%s)", path)
+	f, err := os.Open(path)
+	if err != nil {
+		return Stream{stage: stage, r: strings.NewReader(""), err: pathError("open path", path, err)}
+	}
+	return Stream{stage: stage, r: &fileReader{f: f}}
+}
 
+// fileReader reads a file and closes it when reading is done.
+type fileReader struct {
+	f *os.File
+	// closed indicates if the file was already closed.
+	closed bool
+	// err is the error of closing the file.
+	err error
 }
 
-type multicloser []io.Closer
+func (r *fileReader) Read(b []byte) (int, error) {
+	if r.closed {
+		return 0, io.EOF
+	}
+	n, err := r.f.Read(b)
+	if err != nil {
+		r.Close()
+	}
+	return n, err
+}
 
-func (mc multicloser) Close() error {
-	var errors *multierror.Error
-	for _, c := range mc {
-		if err := c.Close(); err != nil {
-			errors = multierror.Append(errors, err)
+func (r *fileReader) Close() error {
+	if !r.closed {
+		r.closed = true
+		if err := r.f.Close(); err != nil {
+			r.err = pathError("close path", r.f.Name(), err)
 		}
 	}
-	return errors.ErrorOrNil()
+	return r.err
+}
+
+// catReader reads the given files one after the other. It opens each file only when the previous
+// file was fully read.
+type catReader struct {
+	paths []string
+	// cur is the file that is currently being read.
+	cur    *os.File
+	errors *multierror.Error
+}
+
+func (c *catReader) Read(b []byte) (int, error) {
+	for {
+		if c.cur == nil {
+			if len(c.paths) == 0 {
+				return 0, io.EOF
+			}
+			path := c.paths[0]
+			c.paths = c.paths[1:]
+			f, err := os.Open(path)
+			if err != nil {
+				c.errors = multierror.Append(c.errors, pathError("open path", path, err))
+				continue
+			}
+			c.cur = f
+		}
+
+		n, err := c.cur.Read(b)
+		if err != io.EOF {
+			return n, err
+		}
+		// Current file is done, continue to the next one.
+		c.closeCurrent()
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// Close closes the currently read file and returns all the errors that occurred while reading.
+func (c *catReader) Close() error {
+	if c.cur != nil {
+		c.closeCurrent()
+	}
+	return c.errors.ErrorOrNil()
+}
+
+func (c *catReader) closeCurrent() {
+	if err := c.cur.Close(); err != nil {
+		c.errors = multierror.Append(c.errors, pathError("close path", c.cur.Name(), err))
+	}
+	c.cur = nil
 }
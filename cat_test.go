@@ -1,6 +1,8 @@
 package script
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,4 +29,59 @@ func TestCat(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, "", got)
 	})
+
+	t.Run("No such file between files", func(t *testing.T) {
+		got, err := Cat("testdata/a.txt", "testdata/c.txt", "testdata/b.txt").ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "a\nbb\n", got)
+	})
+}
+
+func TestOpenFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("file", func(t *testing.T) {
+		s := OpenFile("testdata/b.txt")
+		require.NoError(t, s.Error())
+		got, err := s.ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "bb\n", got)
+	})
+
+	t.Run("closed at EOF", func(t *testing.T) {
+		s := OpenFile("testdata/b.txt")
+		_, err := ioutil.ReadAll(s)
+		require.NoError(t, err)
+		assert.True(t, s.r.(*fileReader).closed)
+		assert.NoError(t, s.Close())
+	})
+
+	t.Run("no such file", func(t *testing.T) {
+		s := OpenFile("testdata/c.txt")
+		assert.Error(t, s.Error())
+		got, err := s.ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "", got)
+	})
+}
+
+func TestCat_stdin(t *testing.T) {
+	// Create a temporary file to fake stdin.
+	fakeStdin, err := ioutil.TempFile("", "")
+	require.NoError(t, err)
+	defer os.Remove(fakeStdin.Name())
+	defer fakeStdin.Close()
+	_, err = fakeStdin.WriteString("hello world\n")
+	require.NoError(t, err)
+	_, err = fakeStdin.Seek(0, 0)
+	require.NoError(t, err)
+
+	// Temporarely replace stdin with the temporary file.
+	stdin := os.Stdin
+	os.Stdin = fakeStdin
+	defer func() { os.Stdin = stdin }()
+
+	got, err := Cat().ToString()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world\n", got)
 }
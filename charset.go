@@ -0,0 +1,42 @@
+package script
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
+)
+
+// DecodeCharset converts the stream from the given character set to UTF-8. The names are the
+// names and labels of the WHATWG encoding standard, and are case insensitive, such as
+// "windows-1252", "ISO-8859-2" or "shift_jis". An unknown name results in an empty stream with an
+// error. The stream is converted as it is read, and characters that are split between reads are
+// converted correctly.
+//
+// Shell command: `iconv -f <name> -t utf-8`.
+func (s Stream) DecodeCharset(name string) Stream {
+	enc, err := htmlindex.Get(name)
+	d := decodeCharset{name: name, enc: enc}
+	if err != nil {
+		return s.failed(d.Name(), fmt.Errorf("charset %q: %w", name, err))
+	}
+	return s.Through(d)
+}
+
+type decodeCharset struct {
+	name string
+	enc  encoding.Encoding
+}
+
+func (d decodeCharset) Pipe(stdin io.Reader) (io.Reader, error) {
+	return readerWithContext{
+		Reader:  transform.NewReader(stdin, d.enc.NewDecoder()),
+		context: fmt.Sprintf("decode charset %s", d.name),
+	}, nil
+}
+
+func (d decodeCharset) Name() string {
+	return fmt.Sprintf("decode-charset(%s)", d.name)
+}
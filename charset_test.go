@@ -0,0 +1,49 @@
+package script
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCharset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		charset string
+		in      string
+		want    string
+	}{
+		{name: "windows-1252", charset: "windows-1252", in: "caf\xe9 \x80\n", want: "café €\n"},
+		{name: "case insensitive", charset: "ISO-8859-1", in: "caf\xe9\n", want: "café\n"},
+		{name: "latin2", charset: "iso-8859-2", in: "\xb1\n", want: "ą\n"},
+		{name: "utf-8", charset: "utf-8", in: "café\n", want: "café\n"},
+		// Each read returns a single byte, such that multi-byte characters are split between reads.
+		{name: "multi-byte", charset: "shift_jis", in: "\x93\xfa\x96\x7b\n", want: "日本\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := From("in", iotest.OneByteReader(strings.NewReader(tt.in))).DecodeCharset(tt.charset).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("grep", func(t *testing.T) {
+		got, err := From("in", strings.NewReader("caf\xe9\ntea\n")).DecodeCharset("windows-1252").Grep("é").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "café\n", got)
+	})
+
+	t.Run("unknown charset", func(t *testing.T) {
+		got, err := Echo("a").DecodeCharset("no-such-charset").ToString()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `charset "no-such-charset"`)
+		assert.Equal(t, "", got)
+	})
+}
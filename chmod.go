@@ -0,0 +1,40 @@
+package script
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Chmod changes the mode of all the files to the given mode. Only the listed files are changed,
+// directories are not changed recursively. It returns the files with their updated information.
+//
+// If any of the files fails to be changed, it will result in an error, but the other files will
+// still be changed. The returned files contain only the files that were changed successfully, and
+// the returned error is also part of their stream errors.
+//
+// Shell command: `chmod <mode> <files>`.
+func (f Files) Chmod(mode os.FileMode) (Files, error) {
+	var (
+		files  []FileInfo
+		errors *multierror.Error
+	)
+	for _, file := range f.Files {
+		if err := os.Chmod(file.Path, mode); err != nil {
+			errors = multierror.Append(errors, pathError("chmod", file.Path, err))
+			continue
+		}
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			errors = multierror.Append(errors, pathError("stat path", file.Path, err))
+			continue
+		}
+		file.FileInfo = info
+		files = append(files, file)
+	}
+
+	out := f.with(fmt.Sprintf("chmod(%s)", mode), files)
+	out.err = errors.ErrorOrNil()
+	return out, out.err
+}
@@ -0,0 +1,45 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChmod(t *testing.T) {
+	t.Parallel()
+
+	t.Run("chmod", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		files, err := Ls(dir).Chmod(0700)
+		require.NoError(t, err)
+		got, err := files.Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"a.txt", "b"}), got)
+		for _, file := range files.Files {
+			assert.Equal(t, os.FileMode(0700), file.Mode().Perm())
+		}
+
+		// Directory content is not changed.
+		info, err := os.Stat(filepath.Join(dir, "b", "c.txt"))
+		require.NoError(t, err)
+		assert.NotEqual(t, os.FileMode(0700), info.Mode().Perm())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		src := Ls(filepath.Join(dir, "a.txt"), filepath.Join(dir, "b", "c.txt"))
+		require.NoError(t, os.Remove(filepath.Join(dir, "a.txt")))
+
+		files, err := src.Chmod(0600)
+		assert.Error(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "b", "c.txt")}, paths(files))
+	})
+}
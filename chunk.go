@@ -0,0 +1,128 @@
+package script
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Chunk joins each group of n consecutive lines to a single line, separated by the given separator.
+// The last group may contain less than n lines. If n is not positive, each group contains a single
+// line.
+//
+// Shell command: `paste -d<sep> - - ...`.
+func (s Stream) Chunk(n int, sep string) Stream {
+	if n < 1 {
+		n = 1
+	}
+	return s.Modify(&chunk{n: n, sep: []byte(sep)})
+}
+
+// ForEachChunk calls fn with a stream of each group of n consecutive lines, in order, and closes the
+// stream. The last group may contain less than n lines. Each group is stored in memory. If fn
+// returns an error, the iteration stops and the error is returned. If n is not positive, each
+// group contains a single line.
+func (s Stream) ForEachChunk(n int, fn func(chunk Stream) error) error {
+	if n < 1 {
+		n = 1
+	}
+	return s.Modify(&chunkEach{n: n, fn: fn}).To(ioutil.Discard)
+}
+
+// ForEachChunkBytes calls fn with a stream of each consecutive n bytes, in order, and closes the
+// stream. The last chunk may contain less than n bytes. Each chunk is stored in memory. If fn
+// returns an error, the iteration stops and the error is returned.
+func (s Stream) ForEachChunkBytes(n int64, fn func(chunk Stream) error) error {
+	if n < 1 {
+		n = 1
+	}
+	var errors *multierror.Error
+	buf := make([]byte, n)
+	for i := 0; ; i++ {
+		size, err := io.ReadFull(s, buf)
+		if size > 0 {
+			if err := fn(From(fmt.Sprintf("chunk(%d)", i), bytes.NewReader(buf[:size]))); err != nil {
+				errors = multierror.Append(errors, err)
+				break
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			break
+		}
+	}
+	if err := s.Close(); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	return errors.ErrorOrNil()
+}
+
+type chunk struct {
+	n   int
+	sep []byte
+	// count is the number of lines in the current group.
+	count int
+}
+
+func (c *chunk) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		if c.count == 0 {
+			return nil, nil
+		}
+		return []byte{'\n'}, nil
+	}
+	var out []byte
+	if c.count > 0 {
+		out = append(out, c.sep...)
+	}
+	out = append(out, line...)
+	c.count++
+	if c.count == c.n {
+		c.count = 0
+		out = append(out, '\n')
+	}
+	return out, nil
+}
+
+func (c *chunk) Name() string {
+	return fmt.Sprintf("chunk(%d, %q)", c.n, c.sep)
+}
+
+type chunkEach struct {
+	n  int
+	fn func(Stream) error
+	// buf contains the lines of the current group.
+	buf   bytes.Buffer
+	count int
+	// index is the index of the current group.
+	index int
+}
+
+func (c *chunkEach) Modify(line []byte) ([]byte, error) {
+	if line != nil {
+		c.buf.Write(line)
+		c.buf.WriteByte('\n')
+		c.count++
+		if c.count < c.n {
+			return nil, nil
+		}
+	}
+	if c.count == 0 {
+		return nil, nil
+	}
+	chunk := From(fmt.Sprintf("chunk(%d)", c.index), bytes.NewReader(append([]byte(nil), c.buf.Bytes()...)))
+	c.buf.Reset()
+	c.count = 0
+	c.index++
+	return nil, c.fn(chunk)
+}
+
+func (c *chunkEach) Name() string {
+	return fmt.Sprintf("for-each-chunk(%d)", c.n)
+}
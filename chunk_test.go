@@ -0,0 +1,101 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunk(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{in: "a\nb\nc\nd\ne", n: 2, want: "a b\nc d\ne\n"},
+		{in: "a\nb\nc\nd", n: 2, want: "a b\nc d\n"},
+		{in: "a\nb", n: 5, want: "a b\n"},
+		{in: "a\nb", n: 0, want: "a\nb\n"},
+		{in: "", n: 2, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q/%d", tt.in, tt.n), func(t *testing.T) {
+			got, err := From("test", strings.NewReader(tt.in)).Chunk(tt.n, " ").ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestForEachChunk(t *testing.T) {
+	t.Parallel()
+
+	collect := func(chunks *[]string) func(Stream) error {
+		return func(s Stream) error {
+			got, err := s.ToString()
+			*chunks = append(*chunks, got)
+			return err
+		}
+	}
+
+	t.Run("lines", func(t *testing.T) {
+		var chunks []string
+		err := Echo("a\nb\nc").ForEachChunk(2, collect(&chunks))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a\nb\n", "c\n"}, chunks)
+	})
+
+	t.Run("lines error", func(t *testing.T) {
+		calls := 0
+		err := Echo("a\nb\nc").ForEachChunk(1, func(Stream) error {
+			calls++
+			return fmt.Errorf("failed")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("lines upstream error", func(t *testing.T) {
+		var chunks []string
+		err := Cat("testdata/a.txt", "no-such-file", "testdata/b.txt").ForEachChunk(5, collect(&chunks))
+		assert.Error(t, err)
+		assert.Equal(t, []string{"a\nbb\n"}, chunks)
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		var chunks []string
+		err := Echo("abcdefg").ForEachChunkBytes(3, collect(&chunks))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"abc", "def", "g\n"}, chunks)
+	})
+
+	t.Run("bytes exact", func(t *testing.T) {
+		var chunks []string
+		err := Echo("abcde").ForEachChunkBytes(3, collect(&chunks))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"abc", "de\n"}, chunks)
+	})
+
+	t.Run("bytes error", func(t *testing.T) {
+		calls := 0
+		err := Echo("abcdefg").ForEachChunkBytes(3, func(Stream) error {
+			calls++
+			return fmt.Errorf("failed")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("bytes upstream error", func(t *testing.T) {
+		var chunks []string
+		err := Cat("no-such-file", "testdata/b.txt").ForEachChunkBytes(10, collect(&chunks))
+		assert.Error(t, err)
+		assert.Equal(t, []string{"bb\n"}, chunks)
+	})
+}
@@ -0,0 +1,142 @@
+package script
+
+import (
+	"bytes"
+	"fmt"
+	"unicode"
+)
+
+// Column takes the nth white space separated field of each line. The fields are 1 based (first
+// field is 1). Lines that do not have the nth field are omitted.
+//
+// Shell command: `awk '{print $<n>}'`.
+func (s Stream) Column(n int) Stream {
+	return s.Modify(column{n: n})
+}
+
+// ColumnSep takes the nth field of each line, when fields are separated by the given separator.
+// The fields are 1 based (first field is 1). Lines that do not have the nth field are omitted.
+//
+// Shell command: `awk -F<sep> '{print $<n>}'`.
+func (s Stream) ColumnSep(n int, sep string) Stream {
+	return s.Modify(column{n: n, sep: []byte(sep)})
+}
+
+// Columns takes the given white space separated fields of each line, in the given order, and joins
+// them with a space. The fields are 1 based (first field is 1), and may be repeated. Fields that a
+// line does not have are output as empty strings, such that all the output lines have the same
+// number of fields. If no fields are given, all the lines are omitted.
+//
+// Shell command: `awk '{print $<i1>, $<i2>...}'`.
+func (s Stream) Columns(indices ...int) Stream {
+	return s.ColumnsSep(" ", indices...)
+}
+
+// ColumnsSep takes the given white space separated fields of each line, similar to `Columns`, and
+// joins them with the given output separator.
+//
+// Shell command: `awk -v OFS=<sep> '{print $<i1>, $<i2>...}'`.
+func (s Stream) ColumnsSep(sep string, indices ...int) Stream {
+	return s.Modify(columns{indices: indices, sep: []byte(sep)})
+}
+
+// ReplaceColumn replaces the nth white space separated field of each line with the value that f
+// returns for it. The fields are 1 based (first field is 1). The rest of the line, including the
+// white spaces between the fields, is kept as is. Lines that do not have the nth field are not
+// changed.
+//
+// Shell command: `awk '{$<n> = f($<n>); print}'`.
+func (s Stream) ReplaceColumn(n int, f func(field string) string) Stream {
+	return s.Modify(mapLines{
+		name: fmt.Sprintf("replace-column(%d)", n),
+		fn: func(line []byte) []byte {
+			start, end, ok := fieldBounds(line, n)
+			if !ok {
+				return line
+			}
+			out := append([]byte(nil), line[:start]...)
+			out = append(out, f(string(line[start:end]))...)
+			return append(out, line[end:]...)
+		},
+	})
+}
+
+// fieldBounds returns the start and end offsets of the nth white space separated field of the line,
+// where fields are 1 based. It returns false if the line does not have the nth field.
+func fieldBounds(line []byte, n int) (start, end int, ok bool) {
+	field := 0
+	inField := false
+	for i, r := range string(line) {
+		space := unicode.IsSpace(r)
+		switch {
+		case !space && !inField:
+			inField = true
+			field++
+			start = i
+		case space && inField:
+			inField = false
+			if field == n {
+				return start, i, true
+			}
+		}
+	}
+	if inField && field == n {
+		return start, len(line), true
+	}
+	return 0, 0, false
+}
+
+// column is a modifier that takes a single field from each line. If sep is empty, fields are
+// separated by white spaces.
+type column struct {
+	n   int
+	sep []byte
+}
+
+func (c column) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	var fields [][]byte
+	if len(c.sep) == 0 {
+		fields = bytes.Fields(line)
+	} else {
+		fields = bytes.Split(line, c.sep)
+	}
+	if c.n < 1 || c.n > len(fields) {
+		return nil, nil
+	}
+	return append(fields[c.n-1], '\n'), nil
+}
+
+func (c column) Name() string {
+	return fmt.Sprintf("column(%d, sep=%q)", c.n, c.sep)
+}
+
+// columns is a modifier that takes several white space separated fields from each line, and joins
+// them with sep.
+type columns struct {
+	indices []int
+	sep     []byte
+}
+
+func (c columns) Modify(line []byte) ([]byte, error) {
+	if line == nil || len(c.indices) == 0 {
+		return nil, nil
+	}
+	fields := bytes.Fields(line)
+	var out []byte
+	for i, n := range c.indices {
+		if i > 0 {
+			out = append(out, c.sep...)
+		}
+		if n >= 1 && n <= len(fields) {
+			out = append(out, fields[n-1]...)
+		}
+	}
+	return append(out, '\n'), nil
+}
+
+func (c columns) Name() string {
+	return fmt.Sprintf("columns(%v, sep=%q)", c.indices, c.sep)
+}
@@ -0,0 +1,87 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumn(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    Stream
+		want string
+	}{
+		{name: "column", s: Echo("a  b\tc\n  d e f").Column(2), want: "b\ne\n"},
+		{name: "missing column", s: Echo("a b c\nd e").Column(3), want: "c\n"},
+		{name: "zero column", s: Echo("a b c").Column(0), want: ""},
+		{name: "separator", s: Echo("a,b,c\nd,,f").ColumnSep(2, ","), want: "b\n\n"},
+		{name: "multi-byte separator", s: Echo("a::b::c").ColumnSep(3, "::"), want: "c\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestColumns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    Stream
+		want string
+	}{
+		{name: "reorder", s: Echo("a  b\tc\n  d e f").Columns(3, 1), want: "c a\nf d\n"},
+		{name: "repeat", s: Echo("a b").Columns(1, 1, 2), want: "a a b\n"},
+		{name: "missing column", s: Echo("a b c\nd").Columns(1, 3), want: "a c\nd \n"},
+		{name: "zero column", s: Echo("a b").Columns(0, 2), want: " b\n"},
+		{name: "no columns", s: Echo("a b").Columns(), want: ""},
+		{name: "separator", s: Echo("a b c\nd e f").ColumnsSep(",", 2, 3), want: "b,c\ne,f\n"},
+		{name: "empty separator", s: Echo("a b c").ColumnsSep("", 3, 2), want: "cb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplaceColumn(t *testing.T) {
+	t.Parallel()
+
+	upper := func(field string) string { return strings.ToUpper(field) }
+
+	tests := []struct {
+		name string
+		s    Stream
+		want string
+	}{
+		{name: "keeps spacing", s: Echo("a  b\tc\n  d e f").ReplaceColumn(2, upper), want: "a  B\tc\n  d E f\n"},
+		{name: "first", s: Echo("  ab cd").ReplaceColumn(1, upper), want: "  AB cd\n"},
+		{name: "last", s: Echo("ab cd  ").ReplaceColumn(2, upper), want: "ab CD  \n"},
+		{name: "missing column", s: Echo("a b c\nd e").ReplaceColumn(3, upper), want: "a b C\nd e\n"},
+		{name: "zero column", s: Echo("a b").ReplaceColumn(0, upper), want: "a b\n"},
+		{name: "empty line", s: Echo("").ReplaceColumn(1, upper), want: "\n"},
+		{name: "multi-byte", s: Echo("ä ö ü").ReplaceColumn(2, func(string) string { return "***" }), want: "ä *** ü\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package script
+
+import (
+	"io"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Concat creates a stream that outputs the given streams one after the other. Each stream is read
+// only after the previous stream was fully read. The errors of all the streams are kept: the errors
+// that already occurred are returned by the `Error` method of the returned stream, and are followed
+// by the errors that occur while the streams are read, each in the order of the streams. The
+// context of the first stream that has one is kept.
+func Concat(streams ...Stream) Stream {
+	out := Stream{stage: "concat", r: &concatReader{streams: streams}}
+	var errors *multierror.Error
+	for _, s := range streams {
+		if err := s.Error(); err != nil {
+			errors = multierror.Append(errors, err)
+		}
+		if out.ctx == nil {
+			out.ctx = s.ctx
+		}
+	}
+	out.err = errors.ErrorOrNil()
+	return out
+}
+
+type concatReader struct {
+	streams []Stream
+	// cur is the index of the stream that is currently being read.
+	cur int
+}
+
+func (c *concatReader) Read(b []byte) (int, error) {
+	for c.cur < len(c.streams) {
+		n, err := c.streams[c.cur].Read(b)
+		if err == io.EOF {
+			c.cur++
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+	return 0, io.EOF
+}
+
+// Close closes all the streams and returns the errors of closing them. The errors of their stages
+// are not returned, since they are already part of the errors of the concatenated stream.
+func (c *concatReader) Close() error {
+	var errors *multierror.Error
+	for _, s := range c.streams {
+		if err := s.closeReaders(); err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	}
+	return errors.ErrorOrNil()
+}
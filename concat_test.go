@@ -0,0 +1,63 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("concat", func(t *testing.T) {
+		got, err := Concat(Echo("a"), Ls("testdata").Stream, Cat("testdata/b.txt")).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\ntestdata/a.txt\ntestdata/b.txt\nbb\n", got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := Concat().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		got, err := Concat(Ls("no-such-file-1").Stream, Echo("a"), Cat("no-such-file-2")).ToString()
+		require.Error(t, err)
+		assert.Equal(t, "a\n", got)
+
+		merr, ok := err.(*multierror.Error)
+		require.True(t, ok)
+		require.Len(t, merr.Errors, 2)
+		assert.Contains(t, merr.Errors[0].Error(), "no-such-file-1")
+		assert.Contains(t, merr.Errors[1].Error(), "no-such-file-2")
+	})
+
+	t.Run("stream errors", func(t *testing.T) {
+		s := Concat(Echo("a"), Ls("no-such-file").Stream)
+		merr, ok := s.Error().(*multierror.Error)
+		require.True(t, ok)
+		require.Len(t, merr.Errors, 1)
+		assert.True(t, errors.Is(s.Error(), os.ErrNotExist))
+
+		// Errors are not repeated when the stream is closed.
+		_, err := s.ToString()
+		merr, ok = err.(*multierror.Error)
+		require.True(t, ok)
+		assert.Len(t, merr.Errors, 1)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		s := Concat(Echo("a"), Echo("b").WithContext(ctx))
+		assert.Equal(t, ctx, s.ctx)
+		_, err := s.Exec("cat").ToString()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,79 @@
+package script
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// sniffLen is the number of bytes that are read from the beginning of a file in order to detect its
+// content type.
+const sniffLen = 512
+
+// TextOnly filters only files that contain text. A file is considered as text if its first 512
+// bytes do not contain a NUL byte. Directories are omitted. Files that fail to be read result in an
+// error and are omitted.
+//
+// Shell command: `grep -I -l "" <files>`.
+func (f Files) TextOnly() Files {
+	var (
+		files  []FileInfo
+		errors *multierror.Error
+	)
+	for _, file := range f.Files {
+		if file.IsDir() {
+			continue
+		}
+		head, err := file.sniff()
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+		if bytes.IndexByte(head, 0) < 0 {
+			files = append(files, file)
+		}
+	}
+	out := f.with("text-only", files)
+	out.err = errors.ErrorOrNil()
+	return out
+}
+
+// ContentType returns the MIME type of the file, detected from its first 512 bytes using
+// `http.DetectContentType`. When the detection is inconclusive, and results in a generic binary or
+// text type, the type is taken from the file extension if it is known.
+//
+// Shell command: `file -b --mime-type <path>`.
+func (f FileInfo) ContentType() (string, error) {
+	head, err := f.sniff()
+	if err != nil {
+		return "", err
+	}
+	contentType := http.DetectContentType(head)
+	if contentType == "application/octet-stream" || strings.HasPrefix(contentType, "text/plain") {
+		if byExt := mime.TypeByExtension(filepath.Ext(f.Path)); byExt != "" {
+			return byExt, nil
+		}
+	}
+	return contentType, nil
+}
+
+// sniff returns the first bytes of the file.
+func (f FileInfo) sniff() ([]byte, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, pathError("open path", f.Path, err)
+	}
+	defer file.Close()
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, pathError("read path", f.Path, err)
+	}
+	return head[:n], nil
+}
@@ -0,0 +1,75 @@
+package script
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextOnly(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "bin"), []byte("ELF\x00\x01"), 0664))
+	// A NUL byte after the sniffed prefix is not detected.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "late"), []byte(strings.Repeat("a", 600)+"\x00"), 0664))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "empty"), nil, 0664))
+
+	t.Run("text only", func(t *testing.T) {
+		got, err := Ls(dir).TextOnly().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"a.txt", "empty", "late"}), got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		files := Ls(dir)
+		require.NoError(t, os.Remove(filepath.Join(dir, "a.txt")))
+		got, err := files.TextOnly().Slice()
+		assert.Error(t, err)
+		assert.Equal(t, inDir(dir, []string{"empty", "late"}), got)
+	})
+}
+
+func TestContentType(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "a.txt", content: "hello\n", want: "text/plain; charset=utf-8"},
+		{name: "image", content: "\x89PNG\x0d\x0a\x1a\x0a", want: "image/png"},
+		{name: "page", content: "<html><body></body></html>", want: "text/html; charset=utf-8"},
+		{name: "data.json", content: `{"a": 1}`, want: "application/json"},
+		{name: "bin", content: "\x00\x01\x02", want: "application/octet-stream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name)
+			require.NoError(t, ioutil.WriteFile(path, []byte(tt.content), 0664))
+			file, err := Stat(path)
+			require.NoError(t, err)
+
+			got, err := file.ContentType()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		_, err := FileInfo{Path: filepath.Join(dir, "no-such-file")}.ContentType()
+		assert.Error(t, err)
+	})
+}
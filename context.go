@@ -0,0 +1,164 @@
+package script
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithContext returns a stream that stops reading once the given context is done. Reading from the
+// returned stream will then fail with the context error. The context is checked before each read
+// from the previous stages in the stream, interrupts previous sources that wait for data, such as
+// `Get`, `Exec` and `TailFollow`, and is used to kill processes that are executed by following
+// stages.
+func (s Stream) WithContext(ctx context.Context) Stream {
+	s.setContext(ctx)
+	out := s.Through(ctxPipe{ctx: ctx})
+	out.ctx = ctx
+	return out
+}
+
+// WithTimeout returns a stream that stops reading once the given duration passed, similar to
+// `WithContext`. Reading from the returned stream will then fail with `context.DeadlineExceeded`.
+// The timer starts on the first read from the returned stream, such that it measures the time that
+// the stream is being drained. If the stream already has a context, the timeout is derived from it.
+//
+// The timeout is checked before each read from the previous stages, and interrupts previous sources
+// that wait for data, similar to `WithContext`: an HTTP request of `Get` is canceled, a process of
+// `Exec` is killed, and `TailFollow` stops following. The context is released when the stream is
+// closed.
+func (s Stream) WithTimeout(d time.Duration) Stream {
+	parent := s.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	timeout := &timeoutCtx{Context: ctx, cancel: cancel, d: d}
+	s.setContext(timeout)
+	out := s.Through(timeoutPipe{ctx: timeout})
+	out.ctx = timeout
+	return out
+}
+
+// contextSetter is implemented by sources that may wait for data while being read, such that the
+// context of a following `WithContext` or `WithTimeout` stage can interrupt them.
+type contextSetter interface {
+	setContext(ctx context.Context)
+}
+
+// setContext sets the given context on all the stages of the stream that implement contextSetter.
+func (s Stream) setContext(ctx context.Context) {
+	for cur := &s; cur != nil; cur = cur.parent {
+		if setter, ok := cur.r.(contextSetter); ok {
+			setter.setContext(ctx)
+		}
+	}
+}
+
+// doneOf returns the done channel of the given context, or nil, which blocks forever, if the
+// context is nil.
+func doneOf(ctx context.Context) <-chan struct{} {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.Done()
+}
+
+// timeoutCtx is a context that is canceled after a duration from when its timer was started.
+type timeoutCtx struct {
+	context.Context
+	cancel   func()
+	d        time.Duration
+	once     sync.Once
+	timer    *time.Timer
+	timedOut int32
+}
+
+func (c *timeoutCtx) start() {
+	c.once.Do(func() {
+		c.timer = time.AfterFunc(c.d, func() {
+			atomic.StoreInt32(&c.timedOut, 1)
+			c.cancel()
+		})
+	})
+}
+
+// stop prevents the timer from starting, stops it if it was started, and releases the context.
+func (c *timeoutCtx) stop() {
+	c.once.Do(func() {})
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.cancel()
+}
+
+func (c *timeoutCtx) Err() error {
+	if atomic.LoadInt32(&c.timedOut) == 1 {
+		return context.DeadlineExceeded
+	}
+	return c.Context.Err()
+}
+
+// timeoutPipe is a pipe that starts the timer of a timeout context on the first read.
+type timeoutPipe struct {
+	ctx *timeoutCtx
+}
+
+func (p timeoutPipe) Pipe(stdin io.Reader) (io.Reader, error) {
+	return timeoutReader{ctx: p.ctx, r: ctxReader{ctx: p.ctx, r: stdin}}, nil
+}
+
+func (p timeoutPipe) Name() string {
+	return "timeout"
+}
+
+type timeoutReader struct {
+	ctx *timeoutCtx
+	r   io.Reader
+}
+
+func (r timeoutReader) Read(b []byte) (int, error) {
+	r.ctx.start()
+	return r.r.Read(b)
+}
+
+// Close releases the context. It is not released when the input is done, since following stages,
+// such as `Exec`, still use it.
+func (r timeoutReader) Close() error {
+	r.ctx.stop()
+	return nil
+}
+
+// ctxPipe is a pipe that stops reading when a context is done.
+type ctxPipe struct {
+	ctx context.Context
+}
+
+func (p ctxPipe) Pipe(stdin io.Reader) (io.Reader, error) {
+	return ctxReader{ctx: p.ctx, r: stdin}, nil
+}
+
+func (p ctxPipe) Name() string {
+	return "context"
+}
+
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(b []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := r.r.Read(b)
+	if err != nil {
+		// A source that was interrupted by the context may end without an error.
+		if ctxErr := r.ctx.Err(); ctxErr != nil {
+			return n, ctxErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,133 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not canceled", func(t *testing.T) {
+		got, err := Ls("testdata").WithContext(context.Background()).String()
+		require.NoError(t, err)
+		assert.Equal(t, "testdata/a.txt\ntestdata/b.txt\n", got)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		got, err := Ls("testdata").WithContext(ctx).String()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), context.Canceled.Error())
+		assert.Equal(t, "", got)
+	})
+
+	t.Run("canceled between reads", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s := Ls("testdata").WithContext(ctx)
+		defer s.Close()
+
+		buf := make([]byte, 100)
+		n, err := s.Read(buf)
+		require.NoError(t, err)
+		assert.Equal(t, "testdata/a.txt\n", string(buf[:n]))
+
+		cancel()
+		_, err = s.Read(buf)
+		assert.Equal(t, context.Canceled, err)
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not timed out", func(t *testing.T) {
+		got, err := Ls("testdata").WithTimeout(time.Minute).String()
+		require.NoError(t, err)
+		assert.Equal(t, "testdata/a.txt\ntestdata/b.txt\n", got)
+	})
+
+	t.Run("timed out", func(t *testing.T) {
+		slow := slowReader{r: iotest.OneByteReader(Echo("hello world")), delay: 10 * time.Millisecond}
+		got, err := From("slow", slow).WithTimeout(35 * time.Millisecond).String()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+		assert.True(t, len(got) > 0 && len(got) < len("hello world\n"), "got: %q", got)
+	})
+
+	t.Run("timer starts on drain", func(t *testing.T) {
+		s := Ls("testdata").WithTimeout(10 * time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		got, err := s.String()
+		require.NoError(t, err)
+		assert.Equal(t, "testdata/a.txt\ntestdata/b.txt\n", got)
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		start := time.Now()
+		_, err := Echo().WithTimeout(10*time.Millisecond).Exec("sleep", "10").ToString()
+		assert.Error(t, err)
+		assert.True(t, time.Since(start) < 5*time.Second)
+	})
+
+	t.Run("exec source", func(t *testing.T) {
+		start := time.Now()
+		_, err := Exec("sleep", "10").WithTimeout(10 * time.Millisecond).ToString()
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+		assert.True(t, time.Since(start) < 5*time.Second)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+		}))
+		defer server.Close()
+
+		start := time.Now()
+		_, err := Get(server.URL).WithTimeout(10 * time.Millisecond).ToString()
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+		assert.True(t, time.Since(start) < 5*time.Second)
+	})
+
+	t.Run("tail follow", func(t *testing.T) {
+		start := time.Now()
+		got, err := TailFollow("testdata/a.txt").WithTimeout(20 * time.Millisecond).ToString()
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+		assert.Equal(t, "a\n", got)
+		assert.True(t, time.Since(start) < 5*time.Second)
+	})
+
+	t.Run("released on close", func(t *testing.T) {
+		parent, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s := Echo("a").WithContext(parent).WithTimeout(time.Minute)
+		require.NoError(t, s.Close())
+		assert.Error(t, s.ctx.Err())
+		assert.NoError(t, parent.Err())
+	})
+}
+
+// slowReader waits before each read.
+type slowReader struct {
+	r     io.Reader
+	delay time.Duration
+}
+
+func (s slowReader) Read(b []byte) (int, error) {
+	time.Sleep(s.delay)
+	return s.r.Read(b)
+}
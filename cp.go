@@ -0,0 +1,113 @@
+package script
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// CopyTo copies all the files into the given directory, which is created if it does not exist.
+// Existing files in the directory are overwritten. The mode of each file is preserved. It returns
+// the copied files.
+//
+// Files that have the same name, for example from a recursive listing, are not copied, since they
+// would overwrite each other, and each of them results in an error.
+//
+// If any of the files fails to be copied, it will result in an error, but the other files will
+// still be copied. The returned files contain only the files that were copied successfully, and the
+// returned error is also part of their stream errors.
+//
+// Shell command: `cp <files> <dir>`.
+func (f Files) CopyTo(dir string) (Files, error) {
+	return f.CopyToWith(CopyOptions{Overwrite: true}, dir)
+}
+
+// CopyOptions are options for copying files.
+type CopyOptions struct {
+	// Overwrite files that already exist in the destination directory. If not set, copying a file
+	// that already exists results in an error.
+	Overwrite bool
+}
+
+// CopyToWith copies all the files into the given directory, similar to `CopyTo`, according to the
+// given options.
+//
+// Shell command: `cp [-n] <files> <dir>`.
+func (f Files) CopyToWith(opts CopyOptions, dir string) (Files, error) {
+	stage := fmt.Sprintf("cp(%s)", dir)
+	if err := os.MkdirAll(dir, 0775); err != nil {
+		out := f.with(stage, nil)
+		out.err = pathError("create dir", dir, err)
+		return out, out.err
+	}
+
+	var (
+		files  []FileInfo
+		errors *multierror.Error
+	)
+	shared := collisions(dir, f.Files)
+	for _, file := range f.Files {
+		dst := destination(dir, file)
+		if shared[dst] {
+			errors = multierror.Append(errors, collisionError("copy", file, dst))
+			continue
+		}
+		if err := copyFile(file.Path, dst, opts.Overwrite); err != nil {
+			errors = multierror.Append(errors, pathError("copy", file.Path, err))
+			continue
+		}
+		info, err := os.Stat(dst)
+		if err != nil {
+			errors = multierror.Append(errors, pathError("stat path", dst, err))
+			continue
+		}
+		files = append(files, FileInfo{FileInfo: info, Path: dst})
+	}
+
+	out := f.with(stage, files)
+	out.err = errors.ErrorOrNil()
+	return out, out.err
+}
+
+// copyFile copies the content and mode of a regular file. If overwrite is false and dst exists, it
+// fails. It also fails if dst is the same file as src.
+func copyFile(src, dst string, overwrite bool) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("not a regular file")
+	}
+	// Opening dst truncates it, which would destroy src if they are the same file.
+	if dstInfo, err := os.Stat(dst); err == nil && os.SameFile(info, dstInfo) {
+		return fmt.Errorf("%s is the same file", dst)
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !overwrite {
+		flag |= os.O_EXCL
+	}
+	out, err := os.OpenFile(dst, flag, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	// The mode is set explicitly, since it is not applied when the file already exists, and is
+	// affected by the umask when it is created.
+	if err := out.Chmod(info.Mode().Perm()); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,126 @@
+package script
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyTo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("copy", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+		src := filepath.Join(dir, "a.txt")
+		require.NoError(t, os.Chmod(src, 0750))
+		dst := filepath.Join(dir, "dst")
+
+		files, err := Ls(src, filepath.Join(dir, "b")).RegularFiles().CopyTo(dst)
+		require.NoError(t, err)
+		want := inDir(dst, []string{"a.txt", "c.txt"})
+		assert.Equal(t, want, paths(files))
+		got, err := files.Slice()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		content, err := ioutil.ReadFile(filepath.Join(dst, "a.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, src+"\n", string(content))
+		assert.Equal(t, os.FileMode(0750), files.Files[0].Mode().Perm())
+		_, err = os.Stat(src)
+		assert.NoError(t, err)
+	})
+
+	t.Run("same name", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+		dst := filepath.Join(dir, "dst")
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b", "d", "a.txt"), []byte("same\n"), 0664))
+
+		files, err := LsRecursive(dir).RegularFiles().CopyTo(dst)
+		var errs *multierror.Error
+		require.True(t, errors.As(err, &errs))
+		assert.Len(t, errs.Errors, 2, "an error for each file with the same name")
+		assert.Equal(t, []string{filepath.Join(dst, "c.txt"), filepath.Join(dst, "e.txt")}, paths(files))
+		_, err = os.Stat(filepath.Join(dst, "a.txt"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("same directory", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+		src := filepath.Join(dir, "a.txt")
+
+		files, err := Ls(src).CopyTo(dir)
+		var pathErr *PathError
+		require.True(t, errors.As(err, &pathErr), "%v", err)
+		assert.Equal(t, "copy", pathErr.Op)
+		assert.Equal(t, src, pathErr.Path)
+		assert.Empty(t, files.Files)
+		content, err := ioutil.ReadFile(src)
+		require.NoError(t, err)
+		assert.Equal(t, src+"\n", string(content))
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+		dst := filepath.Join(dir, "b")
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dst, "a.txt"), []byte("old"), 0664))
+
+		_, err := Ls(filepath.Join(dir, "a.txt")).CopyTo(dst)
+		require.NoError(t, err)
+		content, err := ioutil.ReadFile(filepath.Join(dst, "a.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "a.txt")+"\n", string(content))
+	})
+
+	t.Run("no overwrite", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+		dst := filepath.Join(dir, "b")
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dst, "a.txt"), []byte("old"), 0664))
+
+		files, err := LsWith(LsOptions{}, dir).RegularFiles().CopyToWith(CopyOptions{}, dst)
+		assert.Error(t, err)
+		assert.Empty(t, files.Files)
+		content, err := ioutil.ReadFile(filepath.Join(dst, "a.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		files, err := Ls(dir).CopyTo(filepath.Join(dir, "dst"))
+		assert.Error(t, err)
+		assert.Equal(t, inDir(dir, []string{"dst/a.txt"}), paths(files))
+	})
+}
+
+func TestCopyFile(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "a.txt")
+
+	dst := filepath.Join(dir, "copy")
+	require.NoError(t, copyFile(src, dst, false))
+	content, err := ioutil.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, src+"\n", string(content))
+
+	assert.Error(t, copyFile(src, dst, false), "dst exists")
+	assert.NoError(t, copyFile(src, dst, true))
+	assert.Error(t, copyFile(src, src, true), "same file")
+	assert.Error(t, copyFile(filepath.Join(dir, "b"), filepath.Join(dir, "copy-dir"), true))
+}
@@ -0,0 +1,41 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVColumn(t *testing.T) {
+	t.Parallel()
+
+	const data = "name,address,age\n\"Doe, John\",\"1 Main St, NY\",42\nJane,\"say \"\"hi\"\"\",7\nshort\n\n"
+
+	tests := []struct {
+		name string
+		s    Stream
+		want string
+	}{
+		{name: "first", s: Echo(data).CSVColumn(1), want: "name\nDoe, John\nJane\nshort\n"},
+		{name: "quoted", s: Echo(data).CSVColumn(2), want: "address\n1 Main St, NY\nsay \"hi\"\n"},
+		{name: "skip header", s: Echo(data).Skip(1).CSVColumn(3), want: "42\n7\n"},
+		{name: "out of range", s: Echo(data).CSVColumn(0), want: ""},
+		{name: "tab", s: Echo("a\tb c\td\n\"e\tf\"\tg").CSVColumnSep(2, '\t'), want: "b c\ng\n"},
+		{name: "semicolon", s: Echo("a;b,c\nd;e").CSVColumnSep(2, ';'), want: "b,c\ne\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("malformed", func(t *testing.T) {
+		got, err := Echo("a,b\n\"c,d\ne,f").CSVColumn(2).ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "b\nf\n", got)
+	})
+}
@@ -0,0 +1,87 @@
+package script
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// DedupByContent removes files that have the same content as a previous file in the list, keeping
+// the first path of each content. Files are compared by size first, and only files with equal sizes
+// are compared by their SHA256 hash. Directories are always kept. Files that fail to be read result
+// in an error and are omitted.
+func (f Files) DedupByContent() Files {
+	var (
+		files  []FileInfo
+		errors *multierror.Error
+	)
+	// Number of files in each size, to avoid hashing files with a unique size.
+	sizes := make(map[int64]int)
+	for _, file := range f.Files {
+		if !file.IsDir() {
+			sizes[file.Size()]++
+		}
+	}
+	seen := make(map[string]bool)
+	for _, file := range f.Files {
+		if file.IsDir() || sizes[file.Size()] == 1 {
+			files = append(files, file)
+			continue
+		}
+		sum, err := fileHash(file.Path)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			continue
+		}
+		key := fmt.Sprintf("%d:%x", file.Size(), sum)
+		if !seen[key] {
+			seen[key] = true
+			files = append(files, file)
+		}
+	}
+	out := f.with("dedup-by-content", files)
+	out.err = errors.ErrorOrNil()
+	return out
+}
+
+// DedupSameFile removes files that are the same file as a previous file in the list, for example
+// hard links or symbolic links that were followed, keeping the first path of each file. It is
+// faster than `DedupByContent` since it does not read the files. Files are compared using
+// `os.SameFile`.
+func (f Files) DedupSameFile() Files {
+	var files []FileInfo
+	// Kept files by their size, since files that have a different size are not the same file.
+	kept := make(map[int64][]FileInfo)
+	for _, file := range f.Files {
+		if !containsSameFile(kept[file.Size()], file) {
+			files = append(files, file)
+			kept[file.Size()] = append(kept[file.Size()], file)
+		}
+	}
+	return f.with("dedup-same-file", files)
+}
+
+func containsSameFile(files []FileInfo, file FileInfo) bool {
+	for _, other := range files {
+		if os.SameFile(other.FileInfo, file.FileInfo) {
+			return true
+		}
+	}
+	return false
+}
+
+func fileHash(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, pathError("open path", path, err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, pathError("read path", path, err)
+	}
+	return h.Sum(nil), nil
+}
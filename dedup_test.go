@@ -0,0 +1,49 @@
+package script
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedup(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for name, content := range map[string]string{"a": "same", "b": "same", "c": "diff", "d": "longer"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0664))
+	}
+	require.NoError(t, os.Link(filepath.Join(dir, "a"), filepath.Join(dir, "e")))
+
+	t.Run("by content", func(t *testing.T) {
+		got, err := Ls(dir).DedupByContent().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"a", "c", "d"}), got)
+	})
+
+	t.Run("same file", func(t *testing.T) {
+		got, err := Ls(dir).DedupSameFile().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"a", "b", "c", "d"}), got)
+	})
+
+	t.Run("by content error", func(t *testing.T) {
+		sub := filepath.Join(dir, "sub")
+		require.NoError(t, os.Mkdir(sub, 0775))
+		for _, name := range []string{"x", "y", "z"} {
+			require.NoError(t, ioutil.WriteFile(filepath.Join(sub, name), []byte("1"), 0664))
+		}
+		files := Ls(sub)
+		require.NoError(t, os.Remove(filepath.Join(sub, "x")))
+
+		got, err := files.DedupByContent().Slice()
+		assert.Error(t, err)
+		assert.Equal(t, inDir(sub, []string{"y"}), got)
+	})
+}
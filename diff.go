@@ -0,0 +1,104 @@
+package script
+
+import (
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Diff compares the lines of the stream with the lines of the other stream, and returns a stream
+// of the lines that differ, in order: lines that appear only in the current stream are prefixed
+// with `-`, and lines that appear only in the other stream are prefixed with `+`. Lines that are
+// common to both streams, according to their longest common subsequence, are omitted. Both
+// streams are fully read and stored in memory, as well as a table of the size of the product of
+// their numbers of lines. The returned error contains the errors of both streams, and is also part
+// of the returned stream errors.
+//
+// Shell command: `diff <(s) <(other) | grep '^[<>]'`.
+func (s Stream) Diff(other Stream) (Stream, error) {
+	a, b, err := readBoth(s, other)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			out.WriteString("-" + a[i] + "\n")
+			i++
+		default:
+			out.WriteString("+" + b[j] + "\n")
+			j++
+		}
+	}
+	return Stream{stage: "diff", r: strings.NewReader(out.String()), err: err}, err
+}
+
+// DiffLines returns a stream of the distinct lines that appear in only one of the streams,
+// regardless of their order: first the lines of the current stream that do not appear in the
+// other stream, prefixed with `-`, and then the lines of the other stream that do not appear in
+// the current stream, prefixed with `+`. Both streams are fully read and stored in memory. The
+// returned error contains the errors of both streams, and is also part of the returned stream
+// errors.
+//
+// Shell command: `comm -3 <(sort -u s) <(sort -u other)`.
+func (s Stream) DiffLines(other Stream) (Stream, error) {
+	a, b, err := readBoth(s, other)
+
+	var out strings.Builder
+	only := func(lines []string, in map[string]bool, prefix string) {
+		seen := make(map[string]bool)
+		for _, line := range lines {
+			if !in[line] && !seen[line] {
+				out.WriteString(prefix + line + "\n")
+			}
+			seen[line] = true
+		}
+	}
+	only(a, lineSet(b), "-")
+	only(b, lineSet(a), "+")
+	return Stream{stage: "diff-lines", r: strings.NewReader(out.String()), err: err}, err
+}
+
+// readBoth reads the lines of both streams, and returns the errors of both.
+func readBoth(s, other Stream) ([]string, []string, error) {
+	var errors *multierror.Error
+	a, err := s.Slice()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	b, err := other.Slice()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	return a, b, errors.ErrorOrNil()
+}
+
+// lineSet returns the set of the given lines.
+func lineSet(lines []string) map[string]bool {
+	m := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		m[line] = true
+	}
+	return m
+}
@@ -0,0 +1,70 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	empty := func() Stream { return From("empty", strings.NewReader("")) }
+
+	tests := []struct {
+		name string
+		a, b Stream
+		want string
+	}{
+		{name: "equal", a: Echo("a\nb"), b: Echo("a\nb"), want: ""},
+		{name: "changed", a: Echo("a\nb\nc"), b: Echo("a\nx\nc"), want: "-b\n+x\n"},
+		{name: "added", a: Echo("a\nc"), b: Echo("a\nb\nc\nd"), want: "+b\n+d\n"},
+		{name: "removed", a: Echo("a\nb\nc"), b: Echo("b"), want: "-a\n-c\n"},
+		{name: "reordered", a: Echo("a\nb\nc\nd"), b: Echo("b\nc\nd\na"), want: "-a\n+a\n"},
+		{name: "empty", a: empty(), b: Echo("a"), want: "+a\n"},
+		{name: "both empty", a: empty(), b: empty(), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := tt.a.Diff(tt.b)
+			require.NoError(t, err)
+			got, err := diff.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("listings", func(t *testing.T) {
+		diff, err := Ls("testdata/a.txt").Diff(Ls("testdata").Stream)
+		require.NoError(t, err)
+		got, err := diff.ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "+testdata/b.txt\n", got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		diff, err := Ls("no-such-file").Diff(Echo("a"))
+		assert.Error(t, err)
+		got, err := diff.ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "+a\n", got)
+	})
+}
+
+func TestDiffLines(t *testing.T) {
+	t.Parallel()
+
+	diff, err := Echo("a\nb\nc\nb").DiffLines(Echo("d\nc\na\nd"))
+	require.NoError(t, err)
+	got, err := diff.ToString()
+	require.NoError(t, err)
+	assert.Equal(t, "-b\n+d\n", got)
+
+	t.Run("error", func(t *testing.T) {
+		_, err := Echo("a").DiffLines(Cat("no-such-file"))
+		assert.Error(t, err)
+	})
+}
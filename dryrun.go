@@ -0,0 +1,84 @@
+package script
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// MoveToDryRun returns a stream with a line for each file that `MoveTo` would move, in the same
+// order, in the form `mv <src> <dst>`, without moving anything or creating the directory. Files
+// that have the same name are omitted, and result in the same errors as in `MoveTo`. It does not
+// check if the other moves would succeed.
+func (f Files) MoveToDryRun(dir string) Stream {
+	return f.dryRun("mv", "move", dir)
+}
+
+// CopyToDryRun returns a stream with a line for each file that `CopyTo` or `CopyToWith` would
+// copy, in the same order, in the form `cp <src> <dst>`, without copying anything or creating the
+// directory. Files that have the same name are omitted, and result in the same errors as in
+// `CopyTo`. It does not check if the other copies would succeed.
+func (f Files) CopyToDryRun(dir string) Stream {
+	return f.dryRun("cp", "copy", dir)
+}
+
+// dryRun returns the lines of moving or copying the files into a directory using the given shell
+// command. The operation is the operation of the errors of files that have the same name.
+func (f Files) dryRun(cmd, op, dir string) Stream {
+	var (
+		out    strings.Builder
+		errors *multierror.Error
+	)
+	shared := collisions(dir, f.Files)
+	for _, file := range f.Files {
+		dst := destination(dir, file)
+		if shared[dst] {
+			errors = multierror.Append(errors, collisionError(op, file, dst))
+			continue
+		}
+		fmt.Fprintf(&out, "%s %s %s\n", cmd, file.Path, dst)
+	}
+	s := f.stream(fmt.Sprintf("%s-dry-run(%s)", cmd, dir), strings.NewReader(out.String()))
+	s.err = errors.ErrorOrNil()
+	return s
+}
+
+// ChmodDryRun returns a stream with a line for each file that `Chmod` would change, in the same
+// order, in the form `chmod <octal permissions> <path>`, without changing anything. It does not
+// check if the changes would succeed.
+func (f Files) ChmodDryRun(mode os.FileMode) Stream {
+	var out strings.Builder
+	for _, file := range f.Files {
+		fmt.Fprintf(&out, "chmod %04o %s\n", mode.Perm(), file.Path)
+	}
+	return f.stream(fmt.Sprintf("chmod-dry-run(%s)", mode), strings.NewReader(out.String()))
+}
+
+// destination returns the path that a file is moved or copied to in the given directory.
+func destination(dir string, file FileInfo) string {
+	return filepath.Join(dir, file.Name())
+}
+
+// collisions returns the destinations in the given directory that different files would be moved
+// or copied to, such that moving or copying them would overwrite each other.
+func collisions(dir string, files []FileInfo) map[string]bool {
+	sources := make(map[string]string, len(files))
+	shared := make(map[string]bool)
+	for _, file := range files {
+		dst := destination(dir, file)
+		if src, ok := sources[dst]; ok && src != file.Path {
+			shared[dst] = true
+		}
+		sources[dst] = file.Path
+	}
+	return shared
+}
+
+// collisionError is the error of a file that is not moved or copied, since its destination is
+// shared with another file.
+func collisionError(op string, file FileInfo, dst string) error {
+	return pathError(op, file.Path, fmt.Errorf("%s is shared with another file", dst))
+}
@@ -0,0 +1,118 @@
+package script
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+	dst := filepath.Join(dir, "dst")
+	files := LsRecursive(dir)
+
+	tests := []struct {
+		name string
+		s    Stream
+		want []string
+	}{
+		{
+			name: "move",
+			s:    files.MoveToDryRun(dst),
+			want: []string{
+				"mv " + filepath.Join(dir, "a.txt") + " " + filepath.Join(dst, "a.txt"),
+				"mv " + filepath.Join(dir, "b/c.txt") + " " + filepath.Join(dst, "c.txt"),
+				"mv " + filepath.Join(dir, "b/d/e.txt") + " " + filepath.Join(dst, "e.txt"),
+			},
+		},
+		{
+			name: "copy",
+			s:    Ls(filepath.Join(dir, "a.txt")).CopyToDryRun(dst),
+			want: []string{"cp " + filepath.Join(dir, "a.txt") + " " + filepath.Join(dst, "a.txt")},
+		},
+		{
+			name: "chmod",
+			s:    files.ChmodDryRun(0600),
+			want: []string{
+				"chmod 0600 " + filepath.Join(dir, "a.txt"),
+				"chmod 0600 " + filepath.Join(dir, "b/c.txt"),
+				"chmod 0600 " + filepath.Join(dir, "b/d/e.txt"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Slice()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("nothing changed", func(t *testing.T) {
+		_, err := os.Stat(dst)
+		assert.True(t, os.IsNotExist(err))
+		got, err := LsRecursive(dir).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, paths(files), got)
+		assert.Equal(t, files.Files[0].Mode(), LsRecursive(dir).Files[0].Mode())
+	})
+
+	t.Run("matches real run", func(t *testing.T) {
+		src := filepath.Join(dir, "b")
+		plan, err := Ls(src).RegularFiles().CopyToDryRun(dst).Slice()
+		require.NoError(t, err)
+		copied, err := Ls(src).RegularFiles().CopyTo(dst)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cp " + filepath.Join(src, "c.txt") + " " + copied.Files[0].Path}, plan)
+	})
+
+	t.Run("same name", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+		dst := filepath.Join(dir, "dst")
+		same := filepath.Join(dir, "b", "d", "a.txt")
+		require.NoError(t, ioutil.WriteFile(same, []byte("same\n"), 0664))
+		files := LsRecursive(dir).RegularFiles()
+
+		for _, tt := range []struct {
+			cmd string
+			s   Stream
+			run func() (Files, error)
+		}{
+			// The files are copied before they are moved.
+			{cmd: "cp", s: files.CopyToDryRun(dst), run: func() (Files, error) { return files.CopyTo(dst) }},
+			{cmd: "mv", s: files.MoveToDryRun(dst), run: func() (Files, error) { return files.MoveTo(dst) }},
+		} {
+			t.Run(tt.cmd, func(t *testing.T) {
+				got, err := tt.s.Slice()
+				assert.Equal(t, []string{
+					tt.cmd + " " + filepath.Join(dir, "b/c.txt") + " " + filepath.Join(dst, "c.txt"),
+					tt.cmd + " " + filepath.Join(dir, "b/d/e.txt") + " " + filepath.Join(dst, "e.txt"),
+				}, got)
+				var errs *multierror.Error
+				require.True(t, errors.As(err, &errs))
+				assert.Len(t, errs.Errors, 2, "an error for each file with the same name")
+
+				// The real run reports the same errors.
+				_, runErr := tt.run()
+				assert.Equal(t, tt.s.Error(), runErr)
+			})
+		}
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Ls("no-such-file", "testdata/a.txt").MoveToDryRun(dst).Slice()
+		assert.Error(t, err)
+		assert.Equal(t, []string{"mv testdata/a.txt " + filepath.Join(dst, "a.txt")}, got)
+	})
+}
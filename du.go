@@ -0,0 +1,35 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TotalSize returns the sum of the sizes of the files, in bytes. Directories in the list are not
+// counted, since their size does not reflect their content, use `LsRecursive` in order to count
+// the files inside directories.
+func (f Files) TotalSize() int64 {
+	var total int64
+	for _, file := range f.Files {
+		if !file.IsDir() {
+			total += file.Size()
+		}
+	}
+	return total
+}
+
+// Du returns a stream with a line for each file that contains its size in bytes and its path,
+// separated by a tab, followed by a line with the total size of the files. Directories are
+// omitted, similar to `TotalSize`.
+//
+// Shell command: `du -a -b -c <files>`.
+func (f Files) Du() Stream {
+	var out strings.Builder
+	for _, file := range f.Files {
+		if !file.IsDir() {
+			fmt.Fprintf(&out, "%d\t%s\n", file.Size(), file.Path)
+		}
+	}
+	fmt.Fprintf(&out, "%d\ttotal\n", f.TotalSize())
+	return f.stream("du", strings.NewReader(out.String()))
+}
@@ -0,0 +1,41 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDu(t *testing.T) {
+	t.Parallel()
+
+	dir := testFiles(t)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "d"), 0775))
+
+	t.Run("total size", func(t *testing.T) {
+		assert.Equal(t, int64(6), Ls(dir).TotalSize())
+		assert.Equal(t, int64(5), LsRecursive("testdata").TotalSize())
+		assert.Equal(t, int64(0), Ls("no-such-file").TotalSize())
+	})
+
+	t.Run("du", func(t *testing.T) {
+		got, err := Ls(dir).SortBySize().Du().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"1\t" + filepath.Join(dir, "c"),
+			"2\t" + filepath.Join(dir, "b"),
+			"3\t" + filepath.Join(dir, "a"),
+			"6\ttotal",
+		}, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		got, err := Ls("no-such-file", "testdata/a.txt").Du().Slice()
+		assert.Error(t, err)
+		assert.Equal(t, []string{"2\ttestdata/a.txt", "2\ttotal"}, got)
+	})
+}
@@ -0,0 +1,119 @@
+package script
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+// Base64Encode encodes the stream using the standard base64 encoding.
+//
+// Shell command: `base64 -w0`.
+func (s Stream) Base64Encode() Stream {
+	return s.Base64EncodeWith(base64.StdEncoding)
+}
+
+// Base64EncodeWith encodes the stream using the given base64 encoding, such as
+// `base64.URLEncoding`.
+func (s Stream) Base64EncodeWith(enc *base64.Encoding) Stream {
+	return s.Through(encodePipe{
+		name:      "base64-encode",
+		newWriter: func(w io.Writer) io.WriteCloser { return base64.NewEncoder(enc, w) },
+	})
+}
+
+// Base64Decode decodes a stream that is encoded using the standard base64 encoding. New lines in
+// the input are ignored.
+//
+// Shell command: `base64 -d`.
+func (s Stream) Base64Decode() Stream {
+	return s.Base64DecodeWith(base64.StdEncoding)
+}
+
+// Base64DecodeWith decodes a stream that is encoded using the given base64 encoding, such as
+// `base64.URLEncoding`. New lines in the input are ignored.
+func (s Stream) Base64DecodeWith(enc *base64.Encoding) Stream {
+	return s.Through(base64Decode{enc: enc})
+}
+
+type base64Decode struct {
+	enc *base64.Encoding
+}
+
+func (d base64Decode) Pipe(stdin io.Reader) (io.Reader, error) {
+	return readerWithContext{Reader: base64.NewDecoder(d.enc, stdin), context: "base64 decode"}, nil
+}
+
+func (d base64Decode) Name() string {
+	return "base64-decode"
+}
+
+// encodePipe is a pipe that passes the stream through an encoding writer, such as a compressor.
+type encodePipe struct {
+	name string
+	// newWriter returns a writer that encodes to the given writer. The writer is closed when the
+	// input is done, to flush any buffered data.
+	newWriter func(w io.Writer) io.WriteCloser
+}
+
+func (e encodePipe) Pipe(stdin io.Reader) (io.Reader, error) {
+	r := &encodeReader{r: stdin}
+	r.w = e.newWriter(&r.buf)
+	return r, nil
+}
+
+func (e encodePipe) Name() string {
+	return e.name
+}
+
+type encodeReader struct {
+	r io.Reader
+	w io.WriteCloser
+	// buf stores encoded data that was not read yet.
+	buf  bytes.Buffer
+	done bool
+}
+
+func (e *encodeReader) Read(b []byte) (int, error) {
+	for e.buf.Len() == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		if err := e.encodeNext(len(b)); err != nil {
+			return 0, err
+		}
+	}
+	return e.buf.Read(b)
+}
+
+// encodeNext reads up to n bytes from the input and encodes them.
+func (e *encodeReader) encodeNext(n int) error {
+	in := make([]byte, n)
+	n, err := e.r.Read(in)
+	if _, err := e.w.Write(in[:n]); err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	if err == io.EOF {
+		e.done = true
+		if err := e.w.Close(); err != nil {
+			return fmt.Errorf("encode: %w", err)
+		}
+		return nil
+	}
+	return err
+}
+
+// readerWithContext adds context to errors of a reader.
+type readerWithContext struct {
+	io.Reader
+	context string
+}
+
+func (r readerWithContext) Read(b []byte) (int, error) {
+	n, err := r.Reader.Read(b)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("%s: %w", r.context, err)
+	}
+	return n, err
+}
@@ -0,0 +1,53 @@
+package script
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase64(t *testing.T) {
+	t.Parallel()
+
+	t.Run("encode", func(t *testing.T) {
+		got, err := Echo("hello world").Base64Encode().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "aGVsbG8gd29ybGQK", got)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		got, err := Echo("aGVsbG8gd29ybGQK").Base64Decode().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "hello world\n", got)
+	})
+
+	t.Run("url encoding", func(t *testing.T) {
+		got, err := From("test", strings.NewReader("\xfb\xff")).Base64EncodeWith(base64.URLEncoding).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "-_8=", got)
+
+		got, err = From("test", strings.NewReader(got)).Base64DecodeWith(base64.URLEncoding).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "\xfb\xff", got)
+	})
+
+	t.Run("round trip of long input", func(t *testing.T) {
+		// Input that is not a multiple of the base64 group size, and is read in multiple reads.
+		in := strings.Repeat("abcdefg", 10001)
+		encoded, err := From("test", strings.NewReader(in)).Base64Encode().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(in)), encoded)
+
+		got, err := From("test", strings.NewReader(encoded)).Base64Decode().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, in, got)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := Echo("not base64!").Base64Decode().ToString()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,37 @@
+package script
+
+import (
+	"fmt"
+	"os"
+)
+
+// PathError is an error of an operation on a specific path that was given to a command, or that a
+// command encountered. For example, when some of the paths given to `Ls` are missing, the stream
+// error contains a `PathError` for each of them, that can be found with `errors.As`.
+type PathError struct {
+	// Op is the operation that failed, such as "stat path".
+	Op string
+	// Path is the path, or the glob pattern, that the operation failed for.
+	Path string
+	// Err is the underlying error.
+	Err error
+}
+
+// pathError returns a `PathError` for the given operation and path. If err is an `*os.PathError`
+// for the same path, only its underlying error is kept, since the path already appears in the error.
+func pathError(op, path string, err error) *PathError {
+	if pe, ok := err.(*os.PathError); ok && pe.Path == path {
+		err = pe.Err
+	}
+	return &PathError{Op: op, Path: path, Err: err}
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error, such that `errors.Is(err, os.ErrNotExist)` works for a
+// missing path.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
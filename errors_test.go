@@ -0,0 +1,160 @@
+package script
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		op   string
+		path string
+	}{
+		{name: "ls", err: Ls("testdata/a.txt", "missing", "testdata/b.txt").Error(), op: "stat path", path: "missing"},
+		{name: "ls glob", err: Ls("testdata/*.go").Error(), op: "glob pattern", path: "testdata/*.go"},
+		{name: "cat", err: Cat("testdata/a.txt", "missing").Discard(), op: "open path", path: "missing"},
+		{name: "open file", err: OpenFile("missing").Error(), op: "open path", path: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.err)
+			var pathErr *PathError
+			require.True(t, errors.As(tt.err, &pathErr))
+			assert.Equal(t, tt.op, pathErr.Op)
+			assert.Equal(t, tt.path, pathErr.Path)
+		})
+	}
+
+	t.Run("not exist", func(t *testing.T) {
+		err := Ls("missing").Error()
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+		assert.Contains(t, err.Error(), "stat path missing: no such file or directory")
+	})
+
+	t.Run("several paths", func(t *testing.T) {
+		var failed []string
+		for _, err := range Ls("missing1", "testdata", "missing2").Errors() {
+			var pathErr *PathError
+			if errors.As(err, &pathErr) {
+				failed = append(failed, pathErr.Path)
+			}
+		}
+		assert.Equal(t, []string{"missing1", "missing2"}, failed)
+	})
+}
+
+func TestPathError_files(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"a", "b", "file"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte("x"), 0664))
+	}
+	// The files are listed, and then removed, such that operations on them fail.
+	missing := filepath.Join(dir, "a")
+	listed := Ls(missing, filepath.Join(dir, "b"))
+	require.NoError(t, os.Remove(missing))
+
+	_, chmodErr := listed.Chmod(0600)
+	_, moveErr := listed.MoveTo(filepath.Join(dir, "mv"))
+	_, copyErr := listed.CopyTo(filepath.Join(dir, "cp"))
+	removeErr := listed.Remove()
+	// A path in a regular file can't be created.
+	notDir := filepath.Join(dir, "file", "c")
+
+	tests := []struct {
+		name   string
+		err    error
+		op     string
+		path   string
+		notDir bool
+	}{
+		{name: "text only", err: listed.TextOnly().Error(), op: "open path", path: missing},
+		{name: "dedup by content", err: listed.DedupByContent().Error(), op: "open path", path: missing},
+		{name: "chmod", err: chmodErr, op: "chmod", path: missing},
+		{name: "move", err: moveErr, op: "move", path: missing},
+		{name: "copy", err: copyErr, op: "copy", path: missing},
+		{name: "remove", err: removeErr, op: "remove", path: missing},
+		{name: "mkdir", err: MkdirAll(notDir, 0775).Error(), op: "create dir", path: notDir, notDir: true},
+		{name: "touch", err: Touch(notDir).Error(), op: "touch", path: notDir, notDir: true},
+		{name: "tail follow", err: TailFollow(missing).Error(), op: "open path", path: missing},
+		{name: "watch", err: Watch(missing).Error(), op: "read dir", path: missing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.err)
+			var pathErr *PathError
+			require.True(t, errors.As(tt.err, &pathErr), "%v", tt.err)
+			assert.Equal(t, tt.op, pathErr.Op)
+			assert.Equal(t, tt.path, pathErr.Path)
+			if !tt.notDir {
+				assert.True(t, errors.Is(tt.err, os.ErrNotExist), "%v", tt.err)
+			}
+		})
+	}
+}
+
+func TestErrorsIs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not exist", func(t *testing.T) {
+		path := tempFile(t, "")
+		listed := Ls(path)
+		require.NoError(t, os.Remove(path))
+		_, chmodErr := listed.Chmod(0600)
+
+		errs := map[string]error{
+			"ls":           Ls("missing").Error(),
+			"ls recursive": LsRecursive("missing").Error(),
+			"cat":          Cat("missing").Discard(),
+			"open file":    OpenFile("missing").Error(),
+			"next stages":  Ls("missing").Grep("x").Head(1).Error(),
+			"chmod":        chmodErr,
+		}
+		for name, err := range errs {
+			assert.True(t, errors.Is(err, os.ErrNotExist), "%s: %v", name, err)
+			assert.False(t, errors.Is(err, os.ErrPermission), "%s: %v", name, err)
+		}
+	})
+
+	t.Run("permission", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("permissions are not enforced for root")
+		}
+		path := tempFile(t, "a\n")
+		require.NoError(t, os.Chmod(path, 0))
+
+		err := Cat(path).Discard()
+		assert.True(t, errors.Is(err, os.ErrPermission), "%v", err)
+		assert.False(t, errors.Is(err, os.ErrNotExist), "%v", err)
+	})
+
+	t.Run("wrapped read error", func(t *testing.T) {
+		readErr := &os.PathError{Op: "read", Path: "file", Err: os.ErrPermission}
+		_, _, err := From("reader", iotestErrReader{readErr}).Peek(1)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, os.ErrPermission), "%v", err)
+		var pathErr *os.PathError
+		require.True(t, errors.As(err, &pathErr))
+		assert.Equal(t, "file", pathErr.Path)
+	})
+}
+
+// iotestErrReader is a reader that always fails with the given error.
+type iotestErrReader struct{ err error }
+
+func (r iotestErrReader) Read([]byte) (int, error) { return 0, r.err }
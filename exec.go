@@ -1,15 +1,26 @@
 package script
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"os"
 	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"text/template"
+	"unicode"
 
 	"github.com/hashicorp/go-multierror"
 )
 
 // Exec executes a command and returns a stream of the stdout of the command.
+//
+// If the command fails, the error of the stream contains an `*ExecError` which holds the exit code
+// and the stderr of the command.
 func Exec(cmd string, args ...string) Stream {
 	return From("empty", nil).Through(exe{cmd: cmd, args: args})
 }
@@ -26,9 +37,11 @@ func ExecHandleStderr(stderr io.Writer, cmd string, args ...string) Stream {
 	return From("empty", nil).Through(exe{cmd: cmd, args: args, stderr: stderr})
 }
 
-// Exec executes a command and returns a stream of the stdout of the command.
+// Exec executes a command and returns a stream of the stdout of the command. The current stream is
+// piped to the stdin of the command. If a context was set using `WithContext`, the command is
+// killed when the context is done.
 func (s Stream) Exec(cmd string, args ...string) Stream {
-	return s.Through(exe{cmd: cmd, args: args})
+	return s.Through(exe{cmd: cmd, args: args, ctx: s.ctx})
 }
 
 // ExecHandleStderr executes a command, returns a stream of the stdout of the command and enable
@@ -36,13 +49,195 @@ func (s Stream) Exec(cmd string, args ...string) Stream {
 //
 // If the stderr is nil, it will be ignored.
 func (s Stream) ExecHandleStderr(stderr io.Writer, cmd string, args ...string) Stream {
-	return s.Through(exe{cmd: cmd, args: args, stderr: stderr})
+	return s.Through(exe{cmd: cmd, args: args, stderr: stderr, ctx: s.ctx})
+}
+
+// Pipe pipes the current stream to the stdin of the given command stream, and returns the stream of
+// the output of the command. The command stream must be created by the package level `Exec` or
+// `ExecHandleStderr` functions, and must not be read before it is piped. The input is copied to
+// the command while it is running, and is not stored in memory. The errors of both streams are
+// combined.
+//
+// For example, `Echo("3\n1\n2").Pipe(Exec("sort"))` is the same as `Echo("3\n1\n2").Exec("sort")`.
+//
+// Shell command: `<s> | <cmd>`.
+func (s Stream) Pipe(cmd Stream) Stream {
+	stage := fmt.Sprintf("pipe(%s)", cmd.stage)
+	source, ok := cmd.r.(*execSource)
+	if !ok || source.connected || source.stdinClosed {
+		cmd.Close()
+		return s.failed(stage, fmt.Errorf("stream %s does not accept input", cmd.stage))
+	}
+	source.connect(s)
+	return Stream{stage: stage, r: streamReader{s: cmd}, parent: &s, ctx: s.ctx}
+}
+
+// ExecForEach executes a command for each line of the stream and returns a stream of the stdout of
+// all the commands. The command is given as a `text/template`, in which the line is available as
+// `{{.}}`. The template is split by white spaces, outside of template actions, to the command name
+// and its arguments, and each of them is rendered separately, such that `{{.}}` is always a single
+// argument, also when the line contains spaces.
+//
+// If the command fails for a line, it will result in an error in the output, but the command will
+// still be executed for the following lines.
+//
+// Shell command: `xargs -I{} <tmpl>`.
+func (s Stream) ExecForEach(tmpl string) Stream {
+	e, err := newExecForEach(tmpl, s.ctx)
+	if err != nil {
+		return s.failed("exec-for-each", err)
+	}
+	return s.Modify(e)
+}
+
+// ExecForEachParallel executes a command for each line of the stream, similar to `ExecForEach`,
+// but runs up to the given number of workers concurrently. The output of the commands is ordered
+// according to the order of the input lines. If workers is not positive, a single worker is used.
+//
+// Shell command: `xargs -P <workers> -I{} <tmpl>`.
+func (s Stream) ExecForEachParallel(workers int, tmpl string) Stream {
+	if workers < 1 {
+		workers = 1
+	}
+	e, err := newExecForEach(tmpl, s.ctx)
+	if err != nil {
+		return s.failed("exec-for-each-parallel", err)
+	}
+	return s.Modify(&parallelLines{
+		name:    fmt.Sprintf("exec-for-each-parallel(%d, %s)", workers, tmpl),
+		workers: workers,
+		fn:      e.exec,
+	})
+}
+
+// execForEach is a modifier that executes a command for each line.
+type execForEach struct {
+	tmpl string
+	// args are the templates of the command name and of each of its arguments.
+	args   []*template.Template
+	ctx    context.Context
+	errors *multierror.Error
+}
+
+func newExecForEach(tmpl string, ctx context.Context) (*execForEach, error) {
+	e := &execForEach{tmpl: tmpl, ctx: ctx}
+	for _, field := range templateFields(tmpl) {
+		t, err := template.New("exec").Parse(field)
+		if err != nil {
+			return nil, fmt.Errorf("parse template: %w", err)
+		}
+		e.args = append(e.args, t)
+	}
+	return e, nil
+}
+
+// templateFields splits a template by white spaces that are not inside template actions.
+func templateFields(tmpl string) []string {
+	var (
+		fields []string
+		field  strings.Builder
+		depth  int
+	)
+	for i := 0; i < len(tmpl); i++ {
+		switch {
+		case strings.HasPrefix(tmpl[i:], "{{"):
+			depth++
+			field.WriteString("{{")
+			i++
+		case depth > 0 && strings.HasPrefix(tmpl[i:], "}}"):
+			depth--
+			field.WriteString("}}")
+			i++
+		case depth == 0 && unicode.IsSpace(rune(tmpl[i])):
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteByte(tmpl[i])
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+func (e *execForEach) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	out, err := e.exec(string(line))
+	if err != nil {
+		e.errors = multierror.Append(e.errors, fmt.Errorf("line %q: %w", line, err))
+	}
+	return out, nil
+}
+
+func (e *execForEach) exec(line string) ([]byte, error) {
+	args := make([]string, 0, len(e.args))
+	for _, t := range e.args {
+		var arg strings.Builder
+		if err := t.Execute(&arg, line); err != nil {
+			return nil, fmt.Errorf("execute template: %w", err)
+		}
+		args = append(args, arg.String())
+	}
+	if len(args) == 0 || args[0] == "" {
+		return nil, fmt.Errorf("empty command")
+	}
+
+	ctx := e.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	x := exe{cmd: args[0], args: args[1:]}
+	cmd := exec.CommandContext(ctx, x.cmd, x.args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start process: %w", err)
+	}
+	err := x.wait(cmd, &stderr)
+	return stdout.Bytes(), err
+}
+
+func (e *execForEach) Close() error {
+	return e.errors.ErrorOrNil()
+}
+
+func (e *execForEach) Name() string {
+	return fmt.Sprintf("exec-for-each(%s)", e.tmpl)
+}
+
+// ExecError is an error of a command that failed.
+type ExecError struct {
+	// Cmd is the name of the failed command.
+	Cmd string
+	// Args are the arguments of the failed command.
+	Args []string
+	// ExitCode is the exit code of the command, or -1 if the command did not exit normally.
+	ExitCode int
+	// Stderr is the stderr output of the command.
+	Stderr string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ExecError) Error() string {
+	msg := fmt.Sprintf("exec(%v, %+v): %v", e.Cmd, e.Args, e.Err)
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		msg += ": " + stderr
+	}
+	return msg
 }
 
 type exe struct {
 	cmd    string
 	args   []string
 	stderr io.Writer
+	ctx    context.Context
 }
 
 func (e exe) Name() string {
@@ -50,34 +245,163 @@ func (e exe) Name() string {
 }
 
 func (e exe) Pipe(stdin io.Reader) (io.Reader, error) {
-	cmd := exec.Command(e.cmd, e.args...)
+	if e.ctx == nil {
+		e.ctx = context.Background()
+	}
+	cmd := exec.CommandContext(e.ctx, e.cmd, e.args...)
 	var errors *multierror.Error
 
-	// Pipe previous stdin if available.
-	if stdin != nil {
-		cmd.Stdin = stdin
+	// Pipe previous stdin if available. Otherwise, the stdin of the command can be connected later
+	// using the `Pipe` method.
+	var source *execSource
+	if f, ok := stdin.(*os.File); ok {
+		cmd.Stdin = f
+	} else if stdin != nil {
+		// The reader is wrapped to prevent the copying to the command from using a WriteTo method,
+		// which closes a stream that is read by the previous stage.
+		cmd.Stdin = struct{ io.Reader }{stdin}
+	} else {
+		w, err := cmd.StdinPipe()
+		if err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("pipe stdin: %w", err))
+		} else {
+			source = &execSource{stdin: w}
+		}
 	}
 
 	// Pipe stdout to the current command output.
 	cmdOut, err := cmd.StdoutPipe()
 	if err != nil {
-		errors = multierror.Append(errors, fmt.Errorf("pipe stdout: %v", err))
+		errors = multierror.Append(errors, fmt.Errorf("pipe stdout: %w", err))
 	}
 
-	if e.stderr == nil {
-		e.stderr = ioutil.Discard
+	// Collect stderr for the error in case that the command fails.
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if e.stderr != nil {
+		cmd.Stderr = io.MultiWriter(e.stderr, &stderr)
 	}
-	cmd.Stderr = e.stderr
 
 	// start the process
 	err = cmd.Start()
 	if err != nil {
-		errors = multierror.Append(errors, fmt.Errorf("start process: %v", err))
+		errors = multierror.Append(errors, fmt.Errorf("start process: %w", err))
 	}
-	return readcloser{
+	out := &execOutput{cmd: cmd, done: make(chan struct{})}
+	out.readcloser = readcloser{
 		Reader: cmdOut,
-		Closer: closerFn(func() error { return cmd.Wait() }),
-	}, errors.ErrorOrNil()
+		Closer: closerFn(func() error {
+			defer out.exited.Do(func() { close(out.done) })
+			return e.wait(cmd, &stderr)
+		}),
+	}
+	if source != nil {
+		source.execOutput = out
+		return source, errors.ErrorOrNil()
+	}
+	return out, errors.ErrorOrNil()
+}
+
+// execOutput is the stdout of a running command, which waits for the command when it is closed.
+type execOutput struct {
+	readcloser
+	cmd *exec.Cmd
+	// done is closed once the command was waited for.
+	done   chan struct{}
+	exited sync.Once
+}
+
+// setContext kills the command when the given context is done, since the command was started
+// before the context was set.
+func (o *execOutput) setContext(ctx context.Context) {
+	if o.cmd.Process == nil {
+		return
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			o.cmd.Process.Kill()
+		case <-o.done:
+		}
+	}()
+}
+
+// execSource is the output of a command that was executed without an input stream. Its stdin is
+// closed on the first read, unless it was connected to another stream using `connect`.
+type execSource struct {
+	*execOutput
+	stdin io.WriteCloser
+	// connected indicates that the stdin is being copied from another stream.
+	connected bool
+	// stdinClosed indicates that the stdin was closed without being connected.
+	stdinClosed bool
+	// copyErr returns the error of copying to stdin, once copying is done.
+	copyErr chan error
+	// closed indicates that the command was closed, and closeErr is the error of closing it.
+	closed   bool
+	closeErr error
+}
+
+func (e *execSource) Read(b []byte) (int, error) {
+	e.closeStdin()
+	return e.execOutput.Read(b)
+}
+
+// connect copies the given reader to the stdin of the command, in the background.
+func (e *execSource) connect(r io.Reader) {
+	e.connected = true
+	e.copyErr = make(chan error, 1)
+	go func() {
+		// The reader is wrapped to prevent io.Copy from using a WriteTo method, which may close it.
+		_, err := io.Copy(e.stdin, struct{ io.Reader }{r})
+		e.stdin.Close()
+		// The command may exit before reading all its input.
+		if errors.Is(err, syscall.EPIPE) || errors.Is(err, os.ErrClosed) {
+			err = nil
+		}
+		e.copyErr <- err
+	}()
+}
+
+func (e *execSource) closeStdin() {
+	if !e.connected && !e.stdinClosed {
+		e.stdinClosed = true
+		e.stdin.Close()
+	}
+}
+
+// Close waits for the copying to stdin and for the command. It can be called several times, and
+// returns the same error each time.
+func (e *execSource) Close() error {
+	if e.closed {
+		return e.closeErr
+	}
+	e.closed = true
+	e.closeStdin()
+	var errs *multierror.Error
+	if e.connected {
+		if err := <-e.copyErr; err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("pipe to stdin: %w", err))
+		}
+	}
+	if err := e.execOutput.Close(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	e.closeErr = errs.ErrorOrNil()
+	return e.closeErr
+}
+
+// wait waits for the command to finish and returns an `*ExecError` if it failed.
+func (e exe) wait(cmd *exec.Cmd, stderr *bytes.Buffer) error {
+	err := cmd.Wait()
+	if err == nil {
+		return nil
+	}
+	exitCode := -1
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		exitCode = exitErr.ExitCode()
+	}
+	return &ExecError{Cmd: e.cmd, Args: e.args, ExitCode: exitCode, Stderr: stderr.String(), Err: err}
 }
 
 type closerFn func() error
@@ -2,8 +2,17 @@ package script
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -25,6 +34,27 @@ func TestExec(t *testing.T) {
 		assert.Equal(t, "hello world\n", stdout)
 	})
 
+	t.Run("stream stdin", func(t *testing.T) {
+		// The input stream is closed once, together with the output stream.
+		stdout, err := From("input", Echo("a").Exec("sh", "-c", "cat; exit 3")).Exec("cat").ToString()
+
+		assert.Equal(t, "a\n", stdout)
+		merr, ok := err.(*multierror.Error)
+		require.True(t, ok)
+		require.Len(t, merr.Errors, 1)
+		var execErr *ExecError
+		require.True(t, errors.As(err, &execErr))
+		assert.Equal(t, 3, execErr.ExitCode)
+	})
+
+	t.Run("fallback stdin", func(t *testing.T) {
+		fallback := func(error) Stream { return Echo("b").Exec("cat") }
+		stdout, err := Fail(fmt.Errorf("failed")).OnError(fallback).Exec("cat").ToString()
+
+		require.NoError(t, err)
+		assert.Equal(t, "b\n", stdout)
+	})
+
 	t.Run("exit code", func(t *testing.T) {
 		stdout, err := Exec("false").ToString()
 
@@ -32,6 +62,37 @@ func TestExec(t *testing.T) {
 		assert.Equal(t, "", stdout)
 	})
 
+	t.Run("exec error", func(t *testing.T) {
+		_, err := Exec("cat", "no-such-file").ToString()
+		require.Error(t, err)
+
+		merr, ok := err.(*multierror.Error)
+		require.True(t, ok)
+		require.Len(t, merr.Errors, 1)
+		execErr, ok := merr.Errors[0].(*ExecError)
+		require.True(t, ok)
+		assert.Equal(t, 1, execErr.ExitCode)
+		assert.Equal(t, "cat: no-such-file: No such file or directory\n", execErr.Stderr)
+		assert.Contains(t, err.Error(), "No such file or directory")
+	})
+
+	t.Run("pipe", func(t *testing.T) {
+		stdout, err := Cat("testdata/b.txt", "testdata/a.txt").Exec("sort").ToString()
+
+		require.NoError(t, err)
+		assert.Equal(t, "a\nbb\n", stdout)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := Echo().WithContext(ctx).Exec("sleep", "10").ToString()
+
+		assert.Error(t, err)
+		assert.True(t, time.Since(start) < 5*time.Second)
+	})
+
 	t.Run("stderr", func(t *testing.T) {
 		var stderr bytes.Buffer
 		stdout, err := ExecHandleStderr(&stderr, "cat", "no-such-file", "testdata/a.txt").ToString()
@@ -41,3 +102,171 @@ func TestExec(t *testing.T) {
 		assert.Equal(t, "cat: no-such-file: No such file or directory\n", stderr.String())
 	})
 }
+
+func TestPipe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pipe", func(t *testing.T) {
+		stdout, err := Echo("3\n1\n2").Pipe(Exec("sort")).ToString()
+
+		require.NoError(t, err)
+		assert.Equal(t, "1\n2\n3\n", stdout)
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		stdout, err := Echo("b\na\nb").Pipe(Exec("sort")).Pipe(Exec("uniq")).Grep("a").ToString()
+
+		require.NoError(t, err)
+		assert.Equal(t, "a\n", stdout)
+	})
+
+	t.Run("then exec", func(t *testing.T) {
+		stdout, err := Echo("3\n1\n2").Pipe(Exec("sort")).Exec("cat").ToString()
+
+		require.NoError(t, err)
+		assert.Equal(t, "1\n2\n3\n", stdout)
+	})
+
+	t.Run("close twice", func(t *testing.T) {
+		s := Echo("a").Pipe(Exec("false"))
+		_, err := s.ToString()
+		assert.Error(t, err)
+		assert.Equal(t, err, s.Close())
+	})
+
+	t.Run("large input", func(t *testing.T) {
+		in := strings.Repeat("line\n", 100000)
+		stdout, err := From("test", strings.NewReader(in)).Pipe(Exec("cat")).ToString()
+
+		require.NoError(t, err)
+		assert.Equal(t, in, stdout)
+	})
+
+	t.Run("command does not read all input", func(t *testing.T) {
+		in := strings.Repeat("line\n", 100000)
+		stdout, err := From("test", strings.NewReader(in)).Pipe(Exec("head", "-n1")).ToString()
+
+		require.NoError(t, err)
+		assert.Equal(t, "line\n", stdout)
+	})
+
+	t.Run("errors of both streams", func(t *testing.T) {
+		_, err := Cat("no-such-file", "testdata/a.txt").Pipe(Exec("false")).ToString()
+
+		require.Error(t, err)
+		merr, ok := err.(*multierror.Error)
+		require.True(t, ok)
+		assert.Len(t, merr.Errors, 2)
+	})
+
+	t.Run("not a command", func(t *testing.T) {
+		_, err := Echo("a").Pipe(Echo("b")).ToString()
+
+		assert.Error(t, err)
+	})
+
+	t.Run("command with input", func(t *testing.T) {
+		_, err := Echo("a").Pipe(Echo("b").Exec("cat")).ToString()
+
+		assert.Error(t, err)
+	})
+
+	t.Run("unread command without input", func(t *testing.T) {
+		// Closing a command that waits for stdin should not block.
+		assert.NoError(t, Exec("cat").Close())
+	})
+}
+
+func TestExecForEach(t *testing.T) {
+	t.Parallel()
+
+	t.Run("for each", func(t *testing.T) {
+		stdout, err := Ls("testdata").ExecForEach("cat {{.}}").ToString()
+
+		require.NoError(t, err)
+		assert.Equal(t, "a\nbb\n", stdout)
+	})
+
+	t.Run("template", func(t *testing.T) {
+		stdout, err := Echo("a\nb").ExecForEach("echo {{.}}-{{.}}").ToString()
+
+		require.NoError(t, err)
+		assert.Equal(t, "a-a\nb-b\n", stdout)
+	})
+
+	t.Run("failed line", func(t *testing.T) {
+		stdout, err := Echo("testdata/a.txt\nno-such-file\ntestdata/b.txt").ExecForEach("cat {{.}}").ToString()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no-such-file")
+		assert.Equal(t, "a\nbb\n", stdout)
+	})
+
+	t.Run("line with spaces", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "script")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "my file.txt")
+		require.NoError(t, ioutil.WriteFile(path, []byte("content\n"), 0664))
+
+		stdout, err := Echo(path).ExecForEach("cat {{.}}").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "content\n", stdout)
+
+		stdout, err = Echo("a  b").ExecForEachParallel(2, "sh -c {{printf \"echo $#\"}} sh {{.}} x").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "2\n", stdout)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		stdout, err := Echo("a").ExecForEach("echo {{").ToString()
+
+		assert.Error(t, err)
+		assert.Equal(t, "", stdout)
+	})
+}
+
+func TestExecForEachParallel(t *testing.T) {
+	t.Parallel()
+
+	// A script that sleeps for the given number of seconds and prints it.
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	script := filepath.Join(dir, "sleep-echo")
+	require.NoError(t, ioutil.WriteFile(script, []byte("#!/bin/sh\nsleep $1\necho $1\n"), 0775))
+
+	t.Run("ordered output", func(t *testing.T) {
+		stdout, err := Echo("0.3\n0.1\n0.2\n0").ExecForEachParallel(2, script+" {{.}}").ToString()
+
+		require.NoError(t, err)
+		assert.Equal(t, "0.3\n0.1\n0.2\n0\n", stdout)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		start := time.Now()
+		stdout, err := Echo("0.3\n0.3\n0.3\n0.3").ExecForEachParallel(4, script+" {{.}}").ToString()
+
+		require.NoError(t, err)
+		assert.Equal(t, "0.3\n0.3\n0.3\n0.3\n", stdout)
+		assert.True(t, time.Since(start) < 1200*time.Millisecond)
+	})
+
+	t.Run("failed line", func(t *testing.T) {
+		stdout, err := Echo("testdata/a.txt\nno-such-file\ntestdata/b.txt\nother-file").ExecForEachParallel(3, "cat {{.}}").ToString()
+
+		require.Error(t, err)
+		merr, ok := err.(*multierror.Error)
+		require.True(t, ok)
+		require.Len(t, merr.Errors, 2)
+		assert.Contains(t, merr.Errors[0].Error(), "no-such-file")
+		assert.Contains(t, merr.Errors[1].Error(), "other-file")
+		assert.Equal(t, "a\nbb\n", stdout)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := Echo("a").ExecForEachParallel(2, "echo {{").ToString()
+
+		assert.Error(t, err)
+	})
+}
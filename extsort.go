@@ -0,0 +1,250 @@
+package script
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// lineMemOverhead is the estimated memory that is used for storing a line, besides its content.
+const lineMemOverhead = 16
+
+// SortLinesExternal returns a stream with lines ordered alphabetically, like `SortLines`, for
+// inputs that do not fit in memory. Lines are stored in memory until their estimated size exceeds
+// maxMem bytes, then they are sorted and written to a temporary file. Once the input is done, the
+// sorted files are merged as the stream is read. If the input fits in maxMem, it is sorted in
+// memory without temporary files. If maxMem is not positive, all the lines are stored in memory.
+//
+// The temporary files are removed once the output is fully read, when an error occurs, or when the
+// stream is closed.
+//
+// Shell command: `sort -S <maxMem>`.
+func (s Stream) SortLinesExternal(maxMem int64) Stream {
+	return s.Through(externalSort{maxMem: maxMem})
+}
+
+type externalSort struct {
+	maxMem int64
+	// tempDir is the directory in which the temporary directory for the sorted files is created.
+	// If it is empty, the default directory for temporary files is used.
+	tempDir string
+}
+
+func (e externalSort) Pipe(stdin io.Reader) (io.Reader, error) {
+	return &externalSortReader{r: stdin, externalSort: e}, nil
+}
+
+func (e externalSort) Name() string {
+	return fmt.Sprintf("sort-external(%d)", e.maxMem)
+}
+
+// externalSortReader reads all the input on the first read, while writing sorted runs of lines to
+// temporary files, and then outputs the merged runs.
+type externalSortReader struct {
+	externalSort
+	r       io.Reader
+	started bool
+	// lines are the lines of the current run, with their estimated size mem.
+	lines []string
+	mem   int64
+	// dir is the temporary directory that contains the sorted runs, which is created with the first
+	// run.
+	dir  string
+	runs []*os.File
+	// merged provides the merged lines of the runs, once all the input was read.
+	merged *runHeap
+	// out holds output that was not read yet.
+	out    bytes.Buffer
+	err    error
+	errors *multierror.Error
+}
+
+func (e *externalSortReader) Read(b []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		if err := e.readInput(); err != nil {
+			e.err = err
+			e.cleanup()
+		}
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	for e.out.Len() < len(b) {
+		line, ok, err := e.next()
+		if err != nil {
+			e.err = err
+			e.cleanup()
+			return 0, err
+		}
+		if !ok {
+			e.cleanup()
+			break
+		}
+		e.out.WriteString(line)
+		e.out.WriteByte('\n')
+	}
+	if e.out.Len() == 0 {
+		return 0, io.EOF
+	}
+	return e.out.Read(b)
+}
+
+// readInput reads all the input lines, and writes sorted runs to temporary files when the lines
+// exceed the memory limit. The lines of the last run are kept in memory if there are no other runs.
+func (e *externalSortReader) readInput() error {
+	r := bufio.NewReader(e.r)
+	for {
+		line, err := r.ReadString('\n')
+		if len(line) > 0 {
+			line = strings.TrimSuffix(line, "\n")
+			e.lines = append(e.lines, line)
+			e.mem += int64(len(line)) + lineMemOverhead
+			if e.maxMem > 0 && e.mem > e.maxMem {
+				if err := e.spill(); err != nil {
+					return err
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	sort.Strings(e.lines)
+	if len(e.runs) == 0 {
+		return nil
+	}
+	if len(e.lines) > 0 {
+		if err := e.spill(); err != nil {
+			return err
+		}
+	}
+	e.merged = &runHeap{}
+	for _, run := range e.runs {
+		if err := e.merged.add(bufio.NewReader(run)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spill writes the current lines, sorted, to a new temporary file.
+func (e *externalSortReader) spill() error {
+	if e.dir == "" {
+		dir, err := ioutil.TempDir(e.tempDir, "script-sort-")
+		if err != nil {
+			return fmt.Errorf("create temp dir: %w", err)
+		}
+		e.dir = dir
+	}
+	f, err := ioutil.TempFile(e.dir, "run-")
+	if err != nil {
+		return fmt.Errorf("create run file: %w", err)
+	}
+	e.runs = append(e.runs, f)
+
+	sort.Strings(e.lines)
+	w := bufio.NewWriter(f)
+	for _, line := range e.lines {
+		w.WriteString(line)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write run file: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("seek run file: %w", err)
+	}
+	e.lines, e.mem = nil, 0
+	return nil
+}
+
+// next returns the next output line, or false if there are no more lines.
+func (e *externalSortReader) next() (string, bool, error) {
+	if e.merged == nil {
+		if len(e.lines) == 0 {
+			return "", false, nil
+		}
+		line := e.lines[0]
+		e.lines = e.lines[1:]
+		return line, true, nil
+	}
+	return e.merged.next()
+}
+
+// cleanup removes the temporary files.
+func (e *externalSortReader) cleanup() {
+	for _, run := range e.runs {
+		if err := run.Close(); err != nil {
+			e.errors = multierror.Append(e.errors, fmt.Errorf("close run file: %w", err))
+		}
+	}
+	e.runs = nil
+	if e.dir != "" {
+		if err := os.RemoveAll(e.dir); err != nil {
+			e.errors = multierror.Append(e.errors, fmt.Errorf("remove temp dir: %w", err))
+		}
+		e.dir = ""
+	}
+	e.lines, e.merged = nil, nil
+}
+
+// Close removes the temporary files and returns the errors that occurred while removing them.
+func (e *externalSortReader) Close() error {
+	e.cleanup()
+	return e.errors.ErrorOrNil()
+}
+
+// runHeap merges sorted runs, by keeping the next line of each of them in a heap.
+type runHeap []runLine
+
+type runLine struct {
+	line string
+	run  *bufio.Reader
+}
+
+// add adds the first line of the given run to the heap.
+func (h *runHeap) add(run *bufio.Reader) error {
+	line, err := run.ReadString('\n')
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read run file: %w", err)
+	}
+	heap.Push(h, runLine{line: strings.TrimSuffix(line, "\n"), run: run})
+	return nil
+}
+
+// next returns the smallest line of all the runs, or false if all the runs are done.
+func (h *runHeap) next() (string, bool, error) {
+	if h.Len() == 0 {
+		return "", false, nil
+	}
+	min := heap.Pop(h).(runLine)
+	return min.line, true, h.add(min.run)
+}
+
+func (h runHeap) Len() int            { return len(h) }
+func (h runHeap) Less(i, j int) bool  { return h[i].line < h[j].line }
+func (h runHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x interface{}) { *h = append(*h, x.(runLine)) }
+func (h *runHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,132 @@
+package script
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortLinesExternal(t *testing.T) {
+	t.Parallel()
+
+	lines := randomLines(1000, 1)
+	want := append([]string(nil), lines...)
+	sort.Strings(want)
+	input := strings.Join(lines, "\n")
+
+	tests := []struct {
+		name   string
+		maxMem int64
+	}{
+		{name: "in memory", maxMem: 1 << 30},
+		{name: "unlimited", maxMem: 0},
+		{name: "several runs", maxMem: 1 << 10},
+		{name: "run per line", maxMem: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "script")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			s := Echo(input).Through(externalSort{maxMem: tt.maxMem, tempDir: dir})
+			got, err := s.Slice()
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+			assertEmptyDir(t, dir)
+
+			// Matches the in memory sort.
+			got, err = Echo(input).SortLinesExternal(tt.maxMem).Slice()
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	t.Run("small reads", func(t *testing.T) {
+		got, err := ioutil.ReadAll(iotest.OneByteReader(Echo("c\na\nb\na").SortLinesExternal(2)))
+		require.NoError(t, err)
+		assert.Equal(t, "a\na\nb\nc\n", string(got))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := From("empty", strings.NewReader("")).SortLinesExternal(1).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+
+	t.Run("empty lines", func(t *testing.T) {
+		got, err := Echo("b\n\na\n").SortLinesExternal(1).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "\n\na\nb\n", got)
+	})
+
+	t.Run("closed before read", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "script")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		s := Echo(input).Through(externalSort{maxMem: 1 << 10, tempDir: dir})
+		buf := make([]byte, 10)
+		_, err = s.Read(buf)
+		require.NoError(t, err)
+		entries, err := ioutil.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1, "sorted runs should be stored in a temporary directory")
+		require.NoError(t, s.Close())
+		assertEmptyDir(t, dir)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Cat("testdata/b.txt", "no-such-file", "testdata/a.txt").SortLinesExternal(1).Slice()
+		assert.Error(t, err)
+		assert.Equal(t, []string{"a", "bb"}, got)
+	})
+}
+
+func BenchmarkSortLines(b *testing.B) {
+	input := strings.Join(randomLines(100000, 1), "\n")
+
+	for _, bm := range []struct {
+		name string
+		sort func(Stream) Stream
+	}{
+		{name: "in memory", sort: Stream.SortLines},
+		{name: "external in memory", sort: func(s Stream) Stream { return s.SortLinesExternal(1 << 30) }},
+		{name: "external 64KB runs", sort: func(s Stream) Stream { return s.SortLinesExternal(64 << 10) }},
+		{name: "external 1MB runs", sort: func(s Stream) Stream { return s.SortLinesExternal(1 << 20) }},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := bm.sort(Echo(input)).Discard(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// randomLines returns n random lines.
+func randomLines(n int, seed int64) []string {
+	rnd := rand.New(rand.NewSource(seed))
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%x", rnd.Int63())
+	}
+	return lines
+}
+
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
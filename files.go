@@ -0,0 +1,383 @@
+package script
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// FileSortKey is a key that files can be sorted by, using `SortBy`.
+type FileSortKey int
+
+const (
+	// SortName sorts files by their path.
+	SortName FileSortKey = iota
+	// SortSize sorts files by their size, from the smallest to the biggest.
+	SortSize
+	// SortModTime sorts files by their modification time, from the oldest to the newest.
+	SortModTime
+)
+
+func (k FileSortKey) String() string {
+	switch k {
+	case SortName:
+		return "name"
+	case SortSize:
+		return "size"
+	case SortModTime:
+		return "mod-time"
+	default:
+		return fmt.Sprintf("FileSortKey(%d)", int(k))
+	}
+}
+
+// compare returns a negative number if a is before b according to the key, a positive number if a
+// is after b, and zero if they are equal.
+func (k FileSortKey) compare(a, b FileInfo) int {
+	switch k {
+	case SortName:
+		return strings.Compare(a.Path, b.Path)
+	case SortSize:
+		return compareInt64(a.Size(), b.Size())
+	case SortModTime:
+		return compareInt64(a.ModTime().UnixNano(), b.ModTime().UnixNano())
+	default:
+		return 0
+	}
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SortBy returns the files sorted by the given keys. Files that are equal according to a key are
+// ordered by the following keys, and files that are equal according to all the keys keep their
+// original order.
+//
+// Usage:
+//
+//  script.LsRecursive("data").SortBy(script.SortSize, script.SortName)
+//
+// Shell command: `ls -l | sort -k<key1> -k<key2>...`.
+func (f Files) SortBy(keys ...FileSortKey) Files {
+	return f.sortBy(fmt.Sprintf("sort-by(%v)", keys), keys...)
+}
+
+// SortByName returns the files sorted by their path. Files with an equal path keep their original
+// order.
+func (f Files) SortByName() Files {
+	return f.sortBy("sort-by-name", SortName)
+}
+
+// SortBySize returns the files sorted by their size, from the smallest to the biggest. Files with
+// an equal size are sorted by their path.
+func (f Files) SortBySize() Files {
+	return f.sortBy("sort-by-size", SortSize, SortName)
+}
+
+// SortByModTime returns the files sorted by their modification time, from the oldest to the
+// newest. Files with an equal modification time are sorted by their path.
+func (f Files) SortByModTime() Files {
+	return f.sortBy("sort-by-mod-time", SortModTime, SortName)
+}
+
+// Reverse returns the files in a reversed order. It can be combined with the sort methods for a
+// descending order, for example `SortByModTime().Reverse()` returns the newest file first.
+func (f Files) Reverse() Files {
+	files := make([]FileInfo, len(f.Files))
+	for i, file := range f.Files {
+		files[len(files)-1-i] = file
+	}
+	return f.with("reverse", files)
+}
+
+// Append returns the files followed by the files of the other list. The errors of both lists are
+// kept.
+func (f Files) Append(other Files) Files {
+	files := make([]FileInfo, 0, len(f.Files)+len(other.Files))
+	files = append(append(files, f.Files...), other.Files...)
+	out := f.with("append", files)
+	out.err = other.Error()
+	return out
+}
+
+// Filter returns only the files for which the keep function returns true.
+func (f Files) Filter(keep func(FileInfo) bool) Files {
+	return f.filter("filter", keep)
+}
+
+// ForEach calls fn for each of the files, in order. Files that fn fails for do not stop the
+// iteration, and all the errors that fn returned are returned together. The stream of the files is
+// not read, such that it can still be used afterwards, and its errors are not part of the returned
+// error.
+func (f Files) ForEach(fn func(FileInfo) error) error {
+	var errors *multierror.Error
+	for _, file := range f.Files {
+		if err := fn(file); err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	}
+	return errors.ErrorOrNil()
+}
+
+// Dirs filters only the directories.
+//
+// Shell command: `find <path> -maxdepth 0 -type d`.
+func (f Files) Dirs() Files {
+	return f.filter("dirs", func(file FileInfo) bool { return file.IsDir() })
+}
+
+// RegularFiles filters only the regular files.
+//
+// Shell command: `find <path> -maxdepth 0 -type f`.
+func (f Files) RegularFiles() Files {
+	return f.filter("regular-files", func(file FileInfo) bool { return file.Mode().IsRegular() })
+}
+
+// LargerThan filters only files that their size is strictly larger than the given number of bytes.
+//
+// Shell command: `find <path> -maxdepth 0 -size +<bytes>c`.
+func (f Files) LargerThan(bytes int64) Files {
+	return f.filter(fmt.Sprintf("larger-than(%d)", bytes), func(file FileInfo) bool { return file.Size() > bytes })
+}
+
+// SmallerThan filters only files that their size is strictly smaller than the given number of
+// bytes.
+//
+// Shell command: `find <path> -maxdepth 0 -size -<bytes>c`.
+func (f Files) SmallerThan(bytes int64) Files {
+	return f.filter(fmt.Sprintf("smaller-than(%d)", bytes), func(file FileInfo) bool { return file.Size() < bytes })
+}
+
+// ModifiedAfter filters only files that were modified after the given time.
+//
+// Shell command: `find <path> -maxdepth 0 -newermt <t>`.
+func (f Files) ModifiedAfter(t time.Time) Files {
+	return f.filter(fmt.Sprintf("modified-after(%s)", t), func(file FileInfo) bool { return file.ModTime().After(t) })
+}
+
+// ModifiedBefore filters only files that were modified before the given time.
+//
+// Shell command: `find <path> -maxdepth 0 ! -newermt <t>`.
+func (f Files) ModifiedBefore(t time.Time) Files {
+	return f.filter(fmt.Sprintf("modified-before(%s)", t), func(file FileInfo) bool { return file.ModTime().Before(t) })
+}
+
+// ModifiedWithin filters only files that were modified in the given duration before now.
+//
+// Shell command: `find <path> -maxdepth 0 -mmin -<d>`.
+func (f Files) ModifiedWithin(d time.Duration) Files {
+	return f.ModifiedAfter(time.Now().Add(-d))
+}
+
+// MatchPath filters only files that their full path matches the given regexp.
+//
+// Shell command: `find <path> -maxdepth 0 -regex <re>`.
+func (f Files) MatchPath(re *regexp.Regexp) Files {
+	return f.filter(fmt.Sprintf("match-path(%v)", re), func(file FileInfo) bool { return re.MatchString(file.Path) })
+}
+
+// RejectPath filters only files that their full path does not match the given regexp.
+//
+// Shell command: `find <path> -maxdepth 0 ! -regex <re>`.
+func (f Files) RejectPath(re *regexp.Regexp) Files {
+	return f.filter(fmt.Sprintf("reject-path(%v)", re), func(file FileInfo) bool { return !re.MatchString(file.Path) })
+}
+
+// filter returns a files object with only the files that the keep function returned true for.
+func (f Files) filter(stage string, keep func(FileInfo) bool) Files {
+	var files []FileInfo
+	for _, file := range f.Files {
+		if keep(file) {
+			files = append(files, file)
+		}
+	}
+	return f.with(stage, files)
+}
+
+// Long returns a stream with a line for each file in a long listing format. Each line contains the
+// file mode, size, modification time and path.
+//
+// Shell command: `ls -l`.
+func (f Files) Long() Stream {
+	return f.long("long", func(size int64) string { return strconv.FormatInt(size, 10) })
+}
+
+// LongHuman returns a stream with a line for each file in a long listing format, similar to `Long`,
+// with human readable sizes. Sizes of at least 1024 bytes are shown in powers of 1024 with a unit
+// suffix, such as `1.2K`, `34M` or `5.6G`, and rounded up, like `ls -lh` does. Smaller sizes,
+// including the sizes of empty files, are shown in bytes.
+//
+// Shell command: `ls -lh`.
+func (f Files) LongHuman() Stream {
+	return f.long("long-human", humanSize)
+}
+
+// long returns a stream with a line for each file in a long listing format, with sizes formatted
+// by the given function. The sizes are aligned to the right.
+func (f Files) long(stage string, formatSize func(int64) string) Stream {
+	sizes := make([]string, len(f.Files))
+	width := 0
+	for i, file := range f.Files {
+		sizes[i] = formatSize(file.Size())
+		if len(sizes[i]) > width {
+			width = len(sizes[i])
+		}
+	}
+
+	var out strings.Builder
+	for i, file := range f.Files {
+		fmt.Fprintf(&out, "%s  %*s  %s  %s\n", file.Mode(), width, sizes[i], file.ModTime().Format(longTimeFormat), file.Path)
+	}
+	return f.stream(stage, strings.NewReader(out.String()))
+}
+
+// humanSize formats the given size in bytes in a human readable format. Values smaller than 10 are
+// shown with a single decimal digit.
+func humanSize(size int64) string {
+	const units = "KMGTPE"
+	if size < 1024 {
+		return strconv.FormatInt(size, 10)
+	}
+	value := float64(size)
+	unit := -1
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if value < 10 {
+		value = math.Ceil(value*10) / 10
+	} else {
+		value = math.Ceil(value)
+	}
+	// Rounding up might reach the next unit.
+	if value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if value < 10 {
+		return fmt.Sprintf("%.1f%c", value, units[unit])
+	}
+	return fmt.Sprintf("%.0f%c", value, units[unit])
+}
+
+// CountByExtension returns a stream with a line for each file extension, with the number of files
+// that have it, ordered by the count from the most common extension to the least common one, as in
+// `Freq`. Files without an extension are counted as `(none)`. Directories are not counted.
+//
+// Shell command: `find <files> -type f | sed 's/.*\.//' | sort | uniq -c | sort -rn`.
+func (f Files) CountByExtension() Stream {
+	var exts strings.Builder
+	for _, file := range f.Files {
+		if file.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(file.Path)
+		if ext == "" {
+			ext = "(none)"
+		}
+		exts.WriteString(ext)
+		exts.WriteByte('\n')
+	}
+	return f.stream("count-by-extension", strings.NewReader(exts.String())).Freq()
+}
+
+// longTimeFormat is the format of modification time in the long listing format.
+const longTimeFormat = "2006-01-02 15:04"
+
+// JSON returns a stream with a JSON array that contains an object for each file. Each object
+// contains the path, size, mode, modification time and whether the file is a directory.
+func (f Files) JSON() Stream {
+	objs := make([]fileJSON, 0, len(f.Files))
+	for _, file := range f.Files {
+		objs = append(objs, newFileJSON(file))
+	}
+	var out bytes.Buffer
+	err := json.NewEncoder(&out).Encode(objs)
+	s := f.stream("json", &out)
+	s.err = err
+	return s
+}
+
+// JSONL returns a stream with a JSON object in each line for each file, in the same format as the
+// objects of `JSON`.
+func (f Files) JSONL() Stream {
+	var (
+		out    bytes.Buffer
+		errors *multierror.Error
+	)
+	enc := json.NewEncoder(&out)
+	for _, file := range f.Files {
+		if err := enc.Encode(newFileJSON(file)); err != nil {
+			errors = multierror.Append(errors, pathError("encode", file.Path, err))
+		}
+	}
+	s := f.stream("jsonl", &out)
+	s.err = errors.ErrorOrNil()
+	return s
+}
+
+// fileJSON is the JSON representation of a file.
+type fileJSON struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"`
+	ModTime time.Time `json:"modTime"`
+	IsDir   bool      `json:"isDir"`
+}
+
+func newFileJSON(f FileInfo) fileJSON {
+	return fileJSON{
+		Path:    f.Path,
+		Size:    f.Size(),
+		Mode:    f.Mode().String(),
+		ModTime: f.ModTime(),
+		IsDir:   f.IsDir(),
+	}
+}
+
+// sortBy returns the files sorted by the given keys, in order.
+func (f Files) sortBy(stage string, keys ...FileSortKey) Files {
+	files := append([]FileInfo(nil), f.Files...)
+	sort.SliceStable(files, func(i, j int) bool {
+		for _, key := range keys {
+			if c := key.compare(files[i], files[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return f.with(stage, files)
+}
+
+// stream returns a stream from the given reader that follows the current stream, such that errors
+// of the current stream are kept.
+func (f Files) stream(stage string, r io.Reader) Stream {
+	return Stream{stage: stage, r: r, parent: &f.Stream, ctx: f.ctx}
+}
+
+// with returns a files object with the given files list. The stream of the returned object follows
+// the current stream, such that errors of the current stream are kept.
+func (f Files) with(stage string, files []FileInfo) Files {
+	out := newFiles(stage, files, nil)
+	out.parent = &f.Stream
+	out.ctx = f.ctx
+	return out
+}
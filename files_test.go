@@ -0,0 +1,475 @@
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesSort(t *testing.T) {
+	t.Parallel()
+
+	dir := testFiles(t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name  string
+		files Files
+		want  []string
+	}{
+		{name: "name", files: Ls(dir).SortByName(), want: []string{"a", "b", "c"}},
+		{name: "size", files: Ls(dir).SortBySize(), want: []string{"c", "b", "a"}},
+		{name: "mod time", files: Ls(dir).SortByModTime(), want: []string{"b", "c", "a"}},
+		{name: "reverse", files: Ls(dir).SortBySize().Reverse(), want: []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, inDir(dir, tt.want), paths(tt.files))
+			got, err := tt.files.Slice()
+			require.NoError(t, err)
+			assert.Equal(t, inDir(dir, tt.want), got)
+		})
+	}
+
+	t.Run("newest first", func(t *testing.T) {
+		files := Ls(dir).SortByModTime().Reverse()
+		assert.Equal(t, inDir(dir, []string{"a", "c", "b"}), paths(files))
+		got, err := files.Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"a", "c", "b"}), got)
+	})
+
+	t.Run("reverse single file", func(t *testing.T) {
+		got, err := Ls("testdata/a.txt").Reverse().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"testdata/a.txt"}, got)
+	})
+
+	t.Run("reverse empty", func(t *testing.T) {
+		files := Ls(filepath.Join(dir, "no-such-file")).Reverse()
+		assert.Empty(t, files.Files)
+		got, err := files.Slice()
+		assert.Error(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("secondary keys", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "script")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		modTime := time.Now().Add(-time.Hour)
+		for _, file := range []struct {
+			name string
+			size int
+		}{{"x", 1}, {"y", 2}, {"z", 1}} {
+			path := filepath.Join(dir, file.name)
+			require.NoError(t, ioutil.WriteFile(path, make([]byte, file.size), 0664))
+			require.NoError(t, os.Chtimes(path, modTime, modTime))
+		}
+		listed := Ls(filepath.Join(dir, "z"), filepath.Join(dir, "y"), filepath.Join(dir, "x"))
+
+		assert.Equal(t, inDir(dir, []string{"x", "z", "y"}), paths(listed.SortBy(SortSize, SortName)))
+		assert.Equal(t, inDir(dir, []string{"z", "x", "y"}), paths(listed.SortBy(SortSize)))
+		assert.Equal(t, inDir(dir, []string{"x", "z", "y"}), paths(listed.SortBySize()))
+		assert.Equal(t, inDir(dir, []string{"x", "y", "z"}), paths(listed.SortByModTime()))
+		assert.Equal(t, inDir(dir, []string{"z", "y", "x"}), paths(listed.SortBy()))
+		got, err := listed.SortBy(SortModTime, SortSize, SortName).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"x", "z", "y"}), got)
+	})
+
+	t.Run("sort key names", func(t *testing.T) {
+		assert.Equal(t, "[size name]", fmt.Sprint([]FileSortKey{SortSize, SortName}))
+		assert.Equal(t, "FileSortKey(7)", FileSortKey(7).String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		got, err := Ls("no-such-file", "testdata").SortByName().Reverse().Slice()
+		assert.Error(t, err)
+		assert.Equal(t, []string{"testdata/b.txt", "testdata/a.txt"}, got)
+	})
+}
+
+func TestFilesAppend(t *testing.T) {
+	t.Parallel()
+
+	dir := testFiles(t)
+	defer os.RemoveAll(dir)
+
+	t.Run("append", func(t *testing.T) {
+		files := Ls(filepath.Join(dir, "c")).Append(Ls("testdata", filepath.Join(dir, "a")))
+		want := []string{filepath.Join(dir, "c"), "testdata/a.txt", "testdata/b.txt", filepath.Join(dir, "a")}
+		assert.Equal(t, want, paths(files))
+		got, err := files.Slice()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("sort", func(t *testing.T) {
+		got, err := Ls(filepath.Join(dir, "c")).Append(Ls(filepath.Join(dir, "a"))).SortByName().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"a", "c"}), got)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		files := Ls("no-such-file", "testdata/a.txt").Append(Ls("other-file"))
+		assert.Len(t, files.Errors(), 2)
+		got, err := files.Slice()
+		assert.Error(t, err)
+		assert.Equal(t, []string{"testdata/a.txt"}, got)
+	})
+}
+
+func TestFilesFilter(t *testing.T) {
+	t.Parallel()
+
+	dir := testFiles(t)
+	defer os.RemoveAll(dir)
+
+	t.Run("filter", func(t *testing.T) {
+		files := Ls(dir).Filter(func(f FileInfo) bool { return f.Size() > 1 })
+		assert.Equal(t, inDir(dir, []string{"a", "b"}), paths(files))
+		got, err := files.Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"a", "b"}), got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		got, err := Ls("no-such-file", "testdata").Filter(func(f FileInfo) bool { return f.Name() == "b.txt" }).Slice()
+		assert.Error(t, err)
+		assert.Equal(t, []string{"testdata/b.txt"}, got)
+	})
+}
+
+func TestFilesForEach(t *testing.T) {
+	t.Parallel()
+
+	dir := testFiles(t)
+	defer os.RemoveAll(dir)
+
+	t.Run("all files", func(t *testing.T) {
+		files := Ls(dir)
+		var sizes []int64
+		err := files.ForEach(func(file FileInfo) error {
+			sizes = append(sizes, file.Size())
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 2, 1}, sizes)
+
+		// The stream is still usable.
+		got, err := files.Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"a", "b", "c"}), got)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var called []string
+		err := Ls(dir).ForEach(func(file FileInfo) error {
+			called = append(called, filepath.Base(file.Path))
+			if file.Size() > 1 {
+				return fmt.Errorf("too big: %s", filepath.Base(file.Path))
+			}
+			return nil
+		})
+		assert.Equal(t, []string{"a", "b", "c"}, called)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "too big: a")
+		assert.Contains(t, err.Error(), "too big: b")
+	})
+
+	t.Run("stream error not returned", func(t *testing.T) {
+		files := Ls("no-such-file", "testdata/a.txt")
+		var called int
+		assert.NoError(t, files.ForEach(func(FileInfo) error { called++; return nil }))
+		assert.Equal(t, 1, called)
+		assert.Error(t, files.Error())
+	})
+}
+
+func TestFilesSize(t *testing.T) {
+	t.Parallel()
+
+	dir := testFiles(t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name  string
+		files Files
+		want  []string
+	}{
+		{name: "larger than", files: Ls(dir).LargerThan(1), want: []string{"a", "b"}},
+		{name: "smaller than", files: Ls(dir).SmallerThan(3), want: []string{"b", "c"}},
+		{name: "sorted", files: Ls(dir).LargerThan(1).SortBySize(), want: []string{"b", "a"}},
+		{name: "none", files: Ls(dir).LargerThan(3), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.files.Slice()
+			require.NoError(t, err)
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, inDir(dir, tt.want), got)
+		})
+	}
+}
+
+func TestFilesModTime(t *testing.T) {
+	t.Parallel()
+
+	dir := testFiles(t)
+	defer os.RemoveAll(dir)
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		files Files
+		want  []string
+	}{
+		{name: "after", files: Ls(dir).ModifiedAfter(now.Add(-4*time.Hour - time.Minute)), want: []string{"a", "c"}},
+		{name: "before", files: Ls(dir).ModifiedBefore(now.Add(-4*time.Hour + time.Minute)), want: []string{"b", "c"}},
+		{name: "within", files: Ls(dir).ModifiedWithin(3*time.Hour + time.Minute), want: []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.files.Slice()
+			require.NoError(t, err)
+			assert.Equal(t, inDir(dir, tt.want), got)
+		})
+	}
+}
+
+func TestFilesType(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+
+	t.Run("dirs", func(t *testing.T) {
+		got, err := Ls(dir, filepath.Join(dir, "b")).Dirs().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"b", "b/d"}), got)
+	})
+
+	t.Run("regular files", func(t *testing.T) {
+		got, err := Ls(dir, filepath.Join(dir, "b")).RegularFiles().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"a.txt", "b/c.txt"}), got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		got, err := Ls("no-such-file", dir).RegularFiles().Slice()
+		assert.Error(t, err)
+		assert.Equal(t, inDir(dir, []string{"a.txt"}), got)
+	})
+}
+
+func TestFilesMatchPath(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name  string
+		files Files
+		want  []string
+	}{
+		{name: "match", files: LsRecursive(dir).MatchPath(regexp.MustCompile(`/b/.*\.txt$`)), want: []string{"b/c.txt", "b/d/e.txt"}},
+		{name: "reject", files: LsRecursive(dir).RejectPath(regexp.MustCompile(`/d/`)), want: []string{"a.txt", "b/c.txt"}},
+		{name: "composed", files: LsRecursive(dir).MatchPath(regexp.MustCompile(`/b/`)).RejectPath(regexp.MustCompile(`e`)), want: []string{"b/c.txt"}},
+		{name: "dirs", files: Ls(dir).MatchPath(regexp.MustCompile(`/b$`)).Dirs(), want: []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.files.Slice()
+			require.NoError(t, err)
+			assert.Equal(t, inDir(dir, tt.want), got)
+			assert.Equal(t, inDir(dir, tt.want), paths(tt.files))
+		})
+	}
+
+	t.Run("none", func(t *testing.T) {
+		got, err := Ls(dir).MatchPath(regexp.MustCompile(`no-match`)).Slice()
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
+
+func TestFilesLong(t *testing.T) {
+	t.Parallel()
+
+	dir := testFiles(t)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b"), make([]byte, 1000), 0664))
+	modTime := time.Date(2020, 1, 2, 15, 4, 0, 0, time.Local)
+	for _, name := range []string{"a", "b"} {
+		require.NoError(t, os.Chtimes(filepath.Join(dir, name), modTime, modTime))
+	}
+
+	files := Ls(filepath.Join(dir, "a"), filepath.Join(dir, "b"))
+	mode := files.Files[0].Mode()
+
+	got, err := files.Long().Slice()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		fmt.Sprintf("%s     3  2020-01-02 15:04  %s", mode, filepath.Join(dir, "a")),
+		fmt.Sprintf("%s  1000  2020-01-02 15:04  %s", mode, filepath.Join(dir, "b")),
+	}, got)
+}
+
+func TestFilesLongHuman(t *testing.T) {
+	t.Parallel()
+
+	dir := testFiles(t)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a"), make([]byte, 1300), 0664))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b"), nil, 0664))
+	modTime := time.Date(2020, 1, 2, 15, 4, 0, 0, time.Local)
+	for _, name := range []string{"a", "b"} {
+		require.NoError(t, os.Chtimes(filepath.Join(dir, name), modTime, modTime))
+	}
+
+	files := Ls(filepath.Join(dir, "a"), filepath.Join(dir, "b"))
+	mode := files.Files[0].Mode()
+
+	got, err := files.LongHuman().Slice()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		fmt.Sprintf("%s  1.3K  2020-01-02 15:04  %s", mode, filepath.Join(dir, "a")),
+		fmt.Sprintf("%s     0  2020-01-02 15:04  %s", mode, filepath.Join(dir, "b")),
+	}, got)
+}
+
+func TestFilesCountByExtension(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"f.go", "b/g.go", "b/d/h.go", "Makefile", "b/i.md"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0664))
+	}
+
+	got, err := LsRecursive(dir).CountByExtension().Slice()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"   3 .go", "   3 .txt", "   1 (none)", "   1 .md"}, got)
+
+	t.Run("directories", func(t *testing.T) {
+		got, err := Ls(dir).CountByExtension().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"   1 (none)", "   1 .go", "   1 .txt"}, got)
+	})
+}
+
+func TestHumanSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{size: 0, want: "0"},
+		{size: 1023, want: "1023"},
+		{size: 1024, want: "1.0K"},
+		{size: 1025, want: "1.1K"},
+		{size: 1229, want: "1.3K"},
+		{size: 10 * 1024, want: "10K"},
+		{size: 10*1024 + 1, want: "11K"},
+		{size: 1024*1024 - 1, want: "1.0M"},
+		{size: 3565158, want: "3.4M"},
+		{size: 6012954214, want: "5.6G"},
+		{size: 1 << 62, want: "4.0E"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, humanSize(tt.size))
+		})
+	}
+}
+
+func TestFilesJSON(t *testing.T) {
+	t.Parallel()
+
+	files := Ls("testdata")
+	modTime := func(i int) string {
+		b, err := json.Marshal(files.Files[i].ModTime())
+		require.NoError(t, err)
+		return string(b)
+	}
+	mode := files.Files[0].Mode()
+
+	t.Run("json", func(t *testing.T) {
+		got, err := files.JSON().ToString()
+		require.NoError(t, err)
+		assert.JSONEq(t, fmt.Sprintf(`[
+			{"path": "testdata/a.txt", "size": 2, "mode": "%s", "modTime": %s, "isDir": false},
+			{"path": "testdata/b.txt", "size": 3, "mode": "%s", "modTime": %s, "isDir": false}
+		]`, mode, modTime(0), mode, modTime(1)), got)
+	})
+
+	t.Run("json lines", func(t *testing.T) {
+		got, err := files.JSONL().Slice()
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.JSONEq(t, fmt.Sprintf(`{"path": "testdata/a.txt", "size": 2, "mode": "%s", "modTime": %s, "isDir": false}`, mode, modTime(0)), got[0])
+		assert.JSONEq(t, fmt.Sprintf(`{"path": "testdata/b.txt", "size": 3, "mode": "%s", "modTime": %s, "isDir": false}`, mode, modTime(1)), got[1])
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := Ls("no-such-file").JSON().ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "[]\n", got)
+	})
+}
+
+// testFiles creates a temporary directory with files of different sizes and modification times,
+// and returns its path:
+//
+//  name | size | modification time
+//  a    | 3    | 3 hours ago
+//  b    | 2    | 5 hours ago
+//  c    | 1    | 4 hours ago
+func testFiles(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	now := time.Now()
+	for _, file := range []struct {
+		name    string
+		size    int
+		modTime time.Time
+	}{
+		{name: "a", size: 3, modTime: now.Add(-3 * time.Hour)},
+		{name: "b", size: 2, modTime: now.Add(-5 * time.Hour)},
+		{name: "c", size: 1, modTime: now.Add(-4 * time.Hour)},
+	} {
+		path := filepath.Join(dir, file.name)
+		require.NoError(t, ioutil.WriteFile(path, make([]byte, file.size), 0664))
+		require.NoError(t, os.Chtimes(path, file.modTime, file.modTime))
+	}
+	return dir
+}
+
+// paths returns the paths of the given files list.
+func paths(f Files) []string {
+	var out []string
+	for _, file := range f.Files {
+		out = append(out, file.Path)
+	}
+	return out
+}
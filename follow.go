@@ -0,0 +1,175 @@
+package script
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// TailFollow outputs the last 10 lines of the given file, and then keeps outputting new data as it
+// is appended to the file. It never ends, see `TailFollowWith` for stopping it with a context.
+//
+// Shell command: `tail -F <path>`.
+func TailFollow(path string) Stream {
+	return TailFollowWith(context.Background(), FollowOptions{}, path)
+}
+
+// FollowOptions are options for following a file.
+type FollowOptions struct {
+	// Lines is the number of last lines of the file to output before following new data. If zero,
+	// the last 10 lines are output. If negative, no existing lines are output.
+	Lines int
+	// PollInterval is the interval for checking the file for new data. If zero, it defaults to 250
+	// milliseconds.
+	PollInterval time.Duration
+}
+
+// TailFollowWith follows a file, similar to `TailFollow`, according to the given options, until the
+// given context is done. When the context is done, the stream ends without an error. If the file is
+// truncated or replaced, for example by log rotation, it is reopened and read from its beginning.
+//
+// Shell command: `tail -F -n <Lines> -s <PollInterval> <path>`.
+func TailFollowWith(ctx context.Context, opts FollowOptions, path string) Stream {
+	stage := fmt.Sprintf("tail -F(%s)", path)
+	if opts.Lines == 0 {
+		opts.Lines = 10
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = 250 * time.Millisecond
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return Stream{stage: stage, r: strings.NewReader(""), err: pathError("open path", path, err), ctx: ctx}
+	}
+	pos, err := lastLinesOffset(f, opts.Lines)
+	if err == nil {
+		_, err = f.Seek(pos, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return Stream{stage: stage, r: strings.NewReader(""), err: pathError("seek path", path, err), ctx: ctx}
+	}
+	return Stream{
+		stage: stage,
+		r:     &followReader{ctx: ctx, path: path, interval: opts.PollInterval, f: f, pos: pos},
+		ctx:   ctx,
+	}
+}
+
+// followReader reads a file, and waits for new data when reaching its end.
+type followReader struct {
+	ctx context.Context
+	// stop is the context of a following stage, which also stops following once it is done.
+	stop     context.Context
+	path     string
+	interval time.Duration
+	f        *os.File
+	// pos is the current read position in the file.
+	pos int64
+}
+
+func (r *followReader) Read(b []byte) (int, error) {
+	for {
+		if r.ctx.Err() != nil || (r.stop != nil && r.stop.Err() != nil) {
+			return 0, io.EOF
+		}
+		n, err := r.f.Read(b)
+		r.pos += int64(n)
+		if n > 0 || (err != nil && err != io.EOF) {
+			return n, err
+		}
+		if err := r.reopenIfChanged(); err != nil {
+			return 0, err
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return 0, io.EOF
+		case <-doneOf(r.stop):
+			return 0, io.EOF
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+func (r *followReader) setContext(ctx context.Context) {
+	r.stop = ctx
+}
+
+// reopenIfChanged reopens the file if it was truncated or replaced by another file.
+func (r *followReader) reopenIfChanged() error {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		// The file may be temporarily missing while being rotated.
+		return nil
+	}
+	current, err := r.f.Stat()
+	if err != nil {
+		return pathError("stat path", r.path, err)
+	}
+	if os.SameFile(info, current) && info.Size() >= r.pos {
+		return nil
+	}
+
+	f, err := os.Open(r.path)
+	if err != nil {
+		return nil
+	}
+	r.f.Close()
+	r.f = f
+	r.pos = 0
+	return nil
+}
+
+func (r *followReader) Close() error {
+	if err := r.f.Close(); err != nil {
+		return pathError("close path", r.path, err)
+	}
+	return nil
+}
+
+// lastLinesOffset returns the offset in the file where its last n lines start. A trailing new line
+// at the end of the file does not start a new line.
+func lastLinesOffset(f *os.File, n int) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	end := info.Size()
+	if n <= 0 {
+		return end, nil
+	}
+
+	const chunkSize = 4096
+	buf := make([]byte, chunkSize)
+	first := true
+	for end > 0 {
+		start := end - chunkSize
+		if start < 0 {
+			start = 0
+		}
+		chunk := buf[:end-start]
+		if _, err := f.ReadAt(chunk, start); err != nil {
+			return 0, err
+		}
+		if first && chunk[len(chunk)-1] == '\n' {
+			// Skip the trailing new line of the file.
+			chunk = chunk[:len(chunk)-1]
+		}
+		first = false
+		for i := bytes.LastIndexByte(chunk, '\n'); i >= 0; i = bytes.LastIndexByte(chunk, '\n') {
+			n--
+			if n == 0 {
+				return start + int64(i) + 1, nil
+			}
+			chunk = chunk[:i]
+		}
+		end = start
+	}
+	return 0, nil
+}
@@ -0,0 +1,135 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailFollow(t *testing.T) {
+	t.Parallel()
+
+	opts := FollowOptions{Lines: 2, PollInterval: 5 * time.Millisecond}
+
+	t.Run("follow", func(t *testing.T) {
+		path := tempFile(t, "a\nb\nc\n")
+		defer os.RemoveAll(filepath.Dir(path))
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			appendToFile(t, path, "d\ne\n")
+		}()
+
+		got, err := TailFollowWith(context.Background(), opts, path).First(4).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "c", "d", "e"}, got)
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		path := tempFile(t, "a\nb\nc\n")
+		defer os.RemoveAll(filepath.Dir(path))
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			require.NoError(t, ioutil.WriteFile(path, []byte("x\n"), 0664))
+		}()
+
+		got, err := TailFollowWith(context.Background(), opts, path).First(3).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "c", "x"}, got)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		path := tempFile(t, "a\n")
+		defer os.RemoveAll(filepath.Dir(path))
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			other := path + ".new"
+			require.NoError(t, ioutil.WriteFile(other, []byte("new file with more content\n"), 0664))
+			require.NoError(t, os.Rename(other, path))
+		}()
+
+		got, err := TailFollowWith(context.Background(), opts, path).First(2).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "new file with more content"}, got)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		path := tempFile(t, "a\nb\nc")
+		defer os.RemoveAll(filepath.Dir(path))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		got, err := TailFollowWith(ctx, FollowOptions{PollInterval: time.Millisecond}, path).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb\nc", got)
+	})
+
+	t.Run("no such file", func(t *testing.T) {
+		_, err := TailFollow("no-such-file").ToString()
+		assert.Error(t, err)
+	})
+}
+
+func TestLastLinesOffset(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("x", 5000)
+
+	tests := []struct {
+		content string
+		n       int
+		want    string
+	}{
+		{content: "a\nb\nc\n", n: 2, want: "b\nc\n"},
+		{content: "a\nb\nc", n: 2, want: "b\nc"},
+		{content: "a\nb\nc\n", n: 5, want: "a\nb\nc\n"},
+		{content: "a\nb\nc\n", n: -1, want: ""},
+		{content: "", n: 2, want: ""},
+		{content: "\n\n", n: 1, want: "\n"},
+		{content: long + "\n" + long + "\n", n: 1, want: long + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%.10q/%d", tt.content, tt.n), func(t *testing.T) {
+			path := tempFile(t, tt.content)
+			defer os.RemoveAll(filepath.Dir(path))
+			f, err := os.Open(path)
+			require.NoError(t, err)
+			defer f.Close()
+
+			offset, err := lastLinesOffset(f, tt.n)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tt.content[offset:])
+		})
+	}
+}
+
+// tempFile creates a temporary file with the given content in a new temporary directory, and returns
+// its path.
+func tempFile(t *testing.T, content string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "file")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0664))
+	return path
+}
+
+func appendToFile(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
@@ -0,0 +1,48 @@
+package script
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Freq counts the number of occurrences of each distinct line, and outputs the lines with their
+// count, ordered by the count from the most frequent line to the least frequent one. Lines with the
+// same count are ordered alphabetically. Only the distinct lines are stored in memory.
+//
+// Shell command: `sort | uniq -c | sort -rn`.
+func (s Stream) Freq() Stream {
+	return s.Modify(&freq{counts: make(map[string]int)})
+}
+
+type freq struct {
+	counts map[string]int
+}
+
+func (f *freq) Modify(line []byte) ([]byte, error) {
+	if line != nil {
+		f.counts[string(line)]++
+		return nil, nil
+	}
+
+	lines := make([]string, 0, len(f.counts))
+	for line := range f.counts {
+		lines = append(lines, line)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if f.counts[lines[i]] != f.counts[lines[j]] {
+			return f.counts[lines[i]] > f.counts[lines[j]]
+		}
+		return lines[i] < lines[j]
+	})
+
+	var out strings.Builder
+	for _, line := range lines {
+		fmt.Fprintf(&out, "%4d %s\n", f.counts[line], line)
+	}
+	return []byte(out.String()), nil
+}
+
+func (f *freq) Name() string {
+	return "freq"
+}
@@ -0,0 +1,31 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreq(t *testing.T) {
+	t.Parallel()
+
+	t.Run("freq", func(t *testing.T) {
+		got, err := Echo("b\na\nc\nb\nc\nb").Freq().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "   3 b\n   2 c\n   1 a\n", got)
+	})
+
+	t.Run("ties", func(t *testing.T) {
+		got, err := Echo("b\na\nb\na").Freq().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "   2 a\n   2 b\n", got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := From("empty", strings.NewReader("")).Freq().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+}
@@ -3,16 +3,27 @@ package script
 import (
 	"bytes"
 	"io"
-	"io/ioutil"
 	"os"
 	"strings"
+	"sync"
 )
 
-// From creates a stream from a reader.
+// From creates a stream from a reader. If the reader is a stream, it is closed when the created
+// stream is closed.
 func From(name string, r io.Reader) Stream {
+	if s, ok := r.(Stream); ok {
+		r = streamReader{s: s}
+	}
 	return Stream{stage: name, r: r}
 }
 
+// FromReader creates a stream from any reader, such as a buffer, a network connection or an HTTP
+// response body. If the reader is also an `io.Closer`, it is closed when the stream is closed,
+// which the terminal methods do after the stream was fully read.
+func FromReader(r io.Reader) Stream {
+	return From("reader", r)
+}
+
 // Writer creates a stream from a function that writes to a writer.
 func Writer(name string, writer func(io.Writer) error) Stream {
 	b := bytes.NewBuffer(nil)
@@ -20,15 +31,39 @@ func Writer(name string, writer func(io.Writer) error) Stream {
 	return Stream{stage: name, r: b, err: err}
 }
 
-// Stdin starts a stream from stdin.
+// Fail creates an empty stream that failed with the given error. The error is available using the
+// stream's `Error` method, and is returned by the terminal method of any stream that follows it.
+// It is useful for testing how errors propagate through a stream.
+func Fail(err error) Stream {
+	return Stream{stage: "fail", r: strings.NewReader(""), err: err}
+}
+
+// Stdin starts a stream that reads from the stdin of the process. It can be used as the source of
+// a stream to write programs that act as filters in a shell pipe.
+//
+// Stdin can be called multiple times, and all the returned streams share the stdin of the
+// process: each part of the input is read only once, by the stream that happens to read it.
+// Closing the stream does not close the stdin of the process.
 func Stdin() Stream {
-	stdin := ioutil.NopCloser(os.Stdin) // Prevent closing of stdin.
 	return From("stdin", stdin)
 }
 
-// Echo writes to stdout.
+// stdin is a reader of the process stdin that can be shared between streams.
+var stdin = &stdinReader{}
+
+type stdinReader struct {
+	mu sync.Mutex
+}
+
+func (r *stdinReader) Read(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return os.Stdin.Read(b)
+}
+
+// Echo writes the given arguments, separated by spaces and followed by a newline, to stdout.
 //
-// Shell command: `echo <s>`
-func Echo(s string) Stream {
-	return From("echo", strings.NewReader(s+"\n"))
+// Shell command: `echo <args>`
+func Echo(args ...string) Stream {
+	return From("echo", strings.NewReader(strings.Join(args, " ")+"\n"))
 }
@@ -1,11 +1,13 @@
 package script
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,9 +17,23 @@ import (
 func TestEcho(t *testing.T) {
 	t.Parallel()
 
-	s, err := Echo("hello world").ToString()
-	require.NoError(t, err)
-	assert.Equal(t, "hello world\n", s)
+	t.Run("single argument", func(t *testing.T) {
+		s, err := Echo("hello world").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "hello world\n", s)
+	})
+
+	t.Run("multiple arguments", func(t *testing.T) {
+		s, err := Echo("hello", "world").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "hello world\n", s)
+	})
+
+	t.Run("no arguments", func(t *testing.T) {
+		s, err := Echo().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "\n", s)
+	})
 }
 
 func TestStdin(t *testing.T) {
@@ -40,6 +56,18 @@ func TestStdin(t *testing.T) {
 	s, err := Stdin().ToString()
 	require.NoError(t, err)
 	assert.Equal(t, "hello world\n", s)
+
+	// Stdin was already consumed.
+	s, err = Stdin().ToString()
+	require.NoError(t, err)
+	assert.Equal(t, "", s)
+
+	// Stdin of the process is not closed.
+	_, err = fakeStdin.Seek(0, 0)
+	require.NoError(t, err)
+	s, err = Stdin().ToString()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world\n", s)
 }
 
 func TestWriter(t *testing.T) {
@@ -54,3 +82,57 @@ func TestWriter_failure(t *testing.T) {
 	_, err := Writer("fail", func(w io.Writer) error { return errors.New("failed") }).ToString()
 	assert.Error(t, err)
 }
+
+func TestFail(t *testing.T) {
+	t.Parallel()
+
+	boom := errors.New("boom")
+
+	t.Run("error", func(t *testing.T) {
+		s := Fail(boom)
+		assert.True(t, errors.Is(s.Error(), boom))
+		got, err := s.ToString()
+		assert.True(t, errors.Is(err, boom))
+		assert.Equal(t, "", got)
+	})
+
+	t.Run("propagates", func(t *testing.T) {
+		got, err := Fail(boom).Grep("x").Sort(false).ToString()
+		assert.True(t, errors.Is(err, boom))
+		assert.Equal(t, "", got)
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		_, err := Fail(boom).Slice()
+		assert.True(t, errors.Is(err, boom))
+	})
+}
+
+func TestFromReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reader", func(t *testing.T) {
+		got, err := FromReader(bytes.NewBufferString("a\nxb\nx")).Grep("x").Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"xb", "x"}, got)
+	})
+
+	t.Run("closer", func(t *testing.T) {
+		r := &closeRecorder{Reader: strings.NewReader("a\n")}
+		got, err := FromReader(r).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\n", got)
+		assert.True(t, r.closed)
+	})
+}
+
+// closeRecorder is a reader that records if it was closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
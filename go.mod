@@ -3,7 +3,7 @@ module github.com/posener/script
 go 1.13
 
 require (
-	github.com/hashicorp/go-multierror v1.0.0
-	github.com/pkg/errors v0.8.1
+	github.com/hashicorp/go-multierror v1.1.1
 	github.com/stretchr/testify v1.4.0
+	golang.org/x/text v0.3.8
 )
@@ -1,27 +1,97 @@
 package script
 
 import (
+	"bytes"
 	"fmt"
+	"path/filepath"
 	"regexp"
 )
 
-// Grep filters only line that match the given regexp.
+// Grep filters only lines that contain the given substring.
+//
+// Shell command: `grep -F <substr>`.
+func (s Stream) Grep(substr string) Stream {
+	return s.Modify(Grep{Substr: substr})
+}
+
+// GrepRegexp filters only lines that match the given regexp.
+//
+// Shell command: `grep <re>`.
+func (s Stream) GrepRegexp(re *regexp.Regexp) Stream {
+	return s.Modify(Grep{Re: re})
+}
+
+// GrepGlob filters only lines that match the given glob pattern, using the `filepath.Match` syntax.
+// The whole line should match the pattern, and the `*` and `?` wildcards do not match the path
+// separator. An invalid pattern results in an empty stream with an error.
+//
+// Shell command: `grep -x <glob>`.
+func (s Stream) GrepGlob(pattern string) Stream {
+	stage := fmt.Sprintf("grep-glob(%q)", pattern)
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return s.failed(stage, fmt.Errorf("invalid glob pattern %q: %w", pattern, err))
+	}
+	return s.Modify(filterLines{
+		name: stage,
+		keep: func(line []byte) bool {
+			ok, _ := filepath.Match(pattern, string(line))
+			return ok
+		},
+	})
+}
+
+// GrepContext filters only lines that contain the given substring, together with before lines that
+// precede each matching line and after lines that follow it. Lines that are in the context of
+// several matching lines are output only once, and a `--` line separates groups of lines that are
+// not adjacent in the input. Only the before lines are stored in memory.
+//
+// Shell command: `grep -F -B <before> -A <after> <substr>`.
+func (s Stream) GrepContext(substr string, before, after int) Stream {
+	if before < 0 {
+		before = 0
+	}
+	return s.Modify(&grepContext{grep: Grep{Substr: substr}, before: before, after: after, last: -1})
+}
+
+// Match filters only lines that contain the given substring. It is the same as `Grep`.
+//
+// Shell command: `grep -F <substr>`.
+func (s Stream) Match(substr string) Stream {
+	return s.Modify(Grep{Substr: substr})
+}
+
+// Reject filters only lines that do not contain the given substring.
+//
+// Shell command: `grep -v -F <substr>`.
+func (s Stream) Reject(substr string) Stream {
+	return s.Modify(Grep{Substr: substr, Inverse: true})
+}
+
+// MatchRegexp filters only lines that match the given regexp. It is the same as `GrepRegexp`.
 //
 // Shell command: `grep <re>`.
-func (s Stream) Grep(re *regexp.Regexp) Stream {
+func (s Stream) MatchRegexp(re *regexp.Regexp) Stream {
 	return s.Modify(Grep{Re: re})
 }
 
-// Grep is a modifier that filters only line that match `Re`. If Invert was set only line that did
-// not match the regex will be returned.
+// RejectRegexp filters only lines that do not match the given regexp.
+//
+// Shell command: `grep -v <re>`.
+func (s Stream) RejectRegexp(re *regexp.Regexp) Stream {
+	return s.Modify(Grep{Re: re, Inverse: true})
+}
+
+// Grep is a modifier that filters only line that match `Re`, or contain `Substr` if `Re` is nil.
+// If Invert was set only line that did not match will be returned.
 //
 // Usage:
 //
 //  (<Stream object>).Modify(script.Grep{Re: <re>})
 //
-// Shell command: `grep [-v <Invert>] <Re>`.
+// Shell command: `grep [-v <Invert>] [-F <Substr>] <Re>`.
 type Grep struct {
 	Re      *regexp.Regexp
+	Substr  string
 	Inverse bool
 }
 
@@ -29,12 +99,73 @@ func (g Grep) Modify(line []byte) (modifed []byte, err error) {
 	if line == nil {
 		return nil, nil
 	}
-	if g.Re.Match(line) != g.Inverse {
+	if g.match(line) != g.Inverse {
 		return append(line, '\n'), nil
 	}
 	return nil, nil
 }
 
 func (g Grep) Name() string {
+	if g.Re == nil {
+		return fmt.Sprintf("grep(%q, invert=%v)", g.Substr, g.Inverse)
+	}
 	return fmt.Sprintf("grep(%v, invert=%v)", g.Re, g.Inverse)
 }
+
+func (g Grep) match(line []byte) bool {
+	if g.Re != nil {
+		return g.Re.Match(line)
+	}
+	return bytes.Contains(line, []byte(g.Substr))
+}
+
+// grepContext is a modifier that outputs matching lines together with their context lines.
+type grepContext struct {
+	grep          Grep
+	before, after int
+	// lines are the last lines that were not output, at most `before` of them.
+	lines [][]byte
+	// i is the index of the current line, and last is the index of the last line that was output.
+	i, last int
+	// afterLeft is the number of lines that should still be output after the last matching line.
+	afterLeft int
+}
+
+func (g *grepContext) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	defer func() { g.i++ }()
+
+	if !g.grep.match(line) {
+		if g.afterLeft > 0 {
+			g.afterLeft--
+			g.last = g.i
+			return append(line, '\n'), nil
+		}
+		if g.before > 0 {
+			if len(g.lines) == g.before {
+				g.lines = append(g.lines[:0], g.lines[1:]...)
+			}
+			g.lines = append(g.lines, line)
+		}
+		return nil, nil
+	}
+
+	var out []byte
+	if first := g.i - len(g.lines); g.last >= 0 && first > g.last+1 {
+		out = append(out, "--\n"...)
+	}
+	for _, l := range g.lines {
+		out = append(append(out, l...), '\n')
+	}
+	out = append(append(out, line...), '\n')
+	g.lines = g.lines[:0]
+	g.afterLeft = g.after
+	g.last = g.i
+	return out, nil
+}
+
+func (g *grepContext) Name() string {
+	return fmt.Sprintf("grep-context(%q, %d, %d)", g.grep.Substr, g.before, g.after)
+}
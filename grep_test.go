@@ -12,7 +12,19 @@ func TestGrep(t *testing.T) {
 	t.Parallel()
 
 	t.Run("grep", func(t *testing.T) {
-		got, err := Echo("a\nb\na\nc").Grep(regexp.MustCompile(`^a`)).ToString()
+		got, err := Echo("a\nb\nba\nc").Grep("a").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\nba\n", got)
+	})
+
+	t.Run("substring is not a regexp", func(t *testing.T) {
+		got, err := Echo("a.b\naxb").Grep(".").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a.b\n", got)
+	})
+
+	t.Run("regexp", func(t *testing.T) {
+		got, err := Echo("a\nb\na\nc").GrepRegexp(regexp.MustCompile(`^a`)).ToString()
 		require.NoError(t, err)
 		assert.Equal(t, "a\na\n", got)
 	})
@@ -22,4 +34,101 @@ func TestGrep(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "b\nc\n", got)
 	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Ls("no-such-file", "testdata/a.txt").Grep("a").ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "testdata/a.txt\n", got)
+	})
+}
+
+func TestGrepGlob(t *testing.T) {
+	t.Parallel()
+
+	const paths = "a.go\na_test.go\nb/b_test.go\nc.txt\nd"
+
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "*_test.go", want: "a_test.go\n"},
+		{pattern: "*/*_test.go", want: "b/b_test.go\n"},
+		{pattern: "?.*", want: "a.go\nc.txt\n"},
+		{pattern: "[a-c].go", want: "a.go\n"},
+		{pattern: "d", want: "d\n"},
+		{pattern: "e*", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := Echo(paths).GrepGlob(tt.pattern).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		got, err := Echo(paths).GrepGlob("[a-").ToString()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid glob pattern "[a-"`)
+		assert.Equal(t, "", got)
+	})
+}
+
+func TestGrepContext(t *testing.T) {
+	t.Parallel()
+
+	const log = "1\n2 x\n3\n4\n5\n6\n7 x\n8\n9 x\n10\n11\n12"
+
+	tests := []struct {
+		name          string
+		before, after int
+		want          string
+	}{
+		{name: "no context", want: "2 x\n--\n7 x\n--\n9 x\n"},
+		{name: "before", before: 1, want: "1\n2 x\n--\n6\n7 x\n8\n9 x\n"},
+		{name: "after", after: 1, want: "2 x\n3\n--\n7 x\n8\n9 x\n10\n"},
+		{name: "merged", before: 2, after: 2, want: "1\n2 x\n3\n4\n5\n6\n7 x\n8\n9 x\n10\n11\n"},
+		{name: "large", before: 10, after: 10, want: log + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Echo(log).GrepContext("x", tt.before, tt.after).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("no match", func(t *testing.T) {
+		got, err := Echo(log).GrepContext("y", 1, 1).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+}
+
+func TestMatchReject(t *testing.T) {
+	t.Parallel()
+
+	const text = "a.go\na_test.go\nb.go"
+	re := regexp.MustCompile(`_test\.go$`)
+
+	tests := []struct {
+		name string
+		s    Stream
+		want string
+	}{
+		{name: "match", s: Echo(text).Match("_test"), want: "a_test.go\n"},
+		{name: "reject", s: Echo(text).Reject("_test"), want: "a.go\nb.go\n"},
+		{name: "match regexp", s: Echo(text).MatchRegexp(re), want: "a_test.go\n"},
+		{name: "reject regexp", s: Echo(text).RejectRegexp(re), want: "a.go\nb.go\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
 }
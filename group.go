@@ -0,0 +1,107 @@
+package script
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GroupBy groups the lines by their nth white space separated field, and outputs a line with the
+// number of lines and the field value for each group, ordered by the field value. The fields are 1
+// based (first field is 1). Lines that do not have the nth field are omitted. Only the distinct
+// field values are stored in memory.
+//
+// Shell command: `awk '{print $<n>}' | sort | uniq -c`.
+func (s Stream) GroupBy(col int) Stream {
+	return s.Modify(&countBy{col: col, counts: make(map[string]int)})
+}
+
+// countBy is a modifier that counts the lines by their nth field, and outputs the counts ordered by
+// the field value.
+type countBy struct {
+	col    int
+	counts map[string]int
+}
+
+func (c *countBy) Modify(line []byte) ([]byte, error) {
+	if line != nil {
+		fields := strings.Fields(string(line))
+		if c.col >= 1 && c.col <= len(fields) {
+			c.counts[fields[c.col-1]]++
+		}
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(c.counts))
+	for key := range c.counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var out strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&out, "%4d %s\n", c.counts[key], key)
+	}
+	return []byte(out.String()), nil
+}
+
+func (c *countBy) Name() string {
+	return fmt.Sprintf("group-by(%d)", c.col)
+}
+
+// ReduceBy groups the lines by the value returned by the key function, and folds the lines of each
+// group using the reduce function. The accumulated value of each group starts as an empty string,
+// and for each line in the group it is replaced by the value returned by the reduce function. For
+// each group, a line with the key and the accumulated value, separated by a space, is output. The
+// groups are ordered by their key. Only the distinct keys and their accumulated values are stored in
+// memory.
+func (s Stream) ReduceBy(key func(line string) string, reduce func(acc, line string) string) Stream {
+	return s.Modify(&reduceBy{key: key, reduce: reduce, groups: make(map[string]string)})
+}
+
+// reduceBy is a modifier that reduces lines by their key and outputs the groups ordered by key.
+type reduceBy struct {
+	key    func(line string) string
+	reduce func(acc, line string) string
+	groups map[string]string
+}
+
+func (r *reduceBy) Modify(line []byte) ([]byte, error) {
+	if line != nil {
+		l := string(line)
+		key := r.key(l)
+		r.groups[key] = r.reduce(r.groups[key], l)
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(r.groups))
+	for key := range r.groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var out strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&out, "%s %s\n", key, r.groups[key])
+	}
+	return []byte(out.String()), nil
+}
+
+func (r *reduceBy) Name() string {
+	return "reduce-by"
+}
+
+// Reduce folds all the lines of the stream into a single value. The accumulated value starts as
+// the given initial value, and for each line it is replaced by the value returned by f. It returns
+// the accumulated value together with the errors that occurred in the stream. The stream is folded
+// as it is read, without storing it.
+func (s Stream) Reduce(initial string, f func(acc, line string) string) (string, error) {
+	acc := initial
+	err := s.Iterate(func(line []byte) error {
+		if line != nil {
+			acc = f(acc, string(line))
+		}
+		return nil
+	})
+	return acc, err
+}
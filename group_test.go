@@ -0,0 +1,105 @@
+package script
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+
+	const log = "GET /a 200\nGET /b 404\nPOST /a 200\nGET /c\nGET /a 500\nGET /b 200"
+
+	tests := []struct {
+		name string
+		s    Stream
+		want string
+	}{
+		{name: "status", s: Echo(log).GroupBy(3), want: "   3 200\n   1 404\n   1 500\n"},
+		{name: "method", s: Echo(log).GroupBy(1), want: "   5 GET\n   1 POST\n"},
+		{name: "no such column", s: Echo(log).GroupBy(4), want: ""},
+		{name: "empty", s: From("empty", strings.NewReader("")).GroupBy(1), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Ls("no-such-file", "testdata/a.txt").GroupBy(1).ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "   1 testdata/a.txt\n", got)
+	})
+}
+
+func TestReduceBy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sum", func(t *testing.T) {
+		key := func(line string) string { return strings.Fields(line)[0] }
+		sum := func(acc, line string) string {
+			a, _ := strconv.Atoi(acc)
+			n, _ := strconv.Atoi(strings.Fields(line)[1])
+			return strconv.Itoa(a + n)
+		}
+		got, err := Echo("b 1\na 2\nb 3\na 4\nc 5").ReduceBy(key, sum).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a 6\nb 4\nc 5\n", got)
+	})
+
+	t.Run("concat", func(t *testing.T) {
+		key := func(line string) string { return line[:1] }
+		concat := func(acc, line string) string { return acc + line[1:] }
+		got, err := Echo("a1\nb1\na2\na3").ReduceBy(key, concat).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a 123\nb 1\n", got)
+	})
+}
+
+func TestReduce(t *testing.T) {
+	t.Parallel()
+
+	sum := func(acc, line string) string {
+		a, _ := strconv.Atoi(acc)
+		n, _ := strconv.Atoi(line)
+		return strconv.Itoa(a + n)
+	}
+
+	t.Run("sum", func(t *testing.T) {
+		got, err := Echo("a 1\nb 2\nc 3").Column(2).Reduce("0", sum)
+		require.NoError(t, err)
+		assert.Equal(t, "6", got)
+	})
+
+	t.Run("max", func(t *testing.T) {
+		max := func(acc, line string) string {
+			if len(line) > len(acc) {
+				return line
+			}
+			return acc
+		}
+		got, err := Echo("bb\nccc\na").Reduce("", max)
+		require.NoError(t, err)
+		assert.Equal(t, "ccc", got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := From("empty", strings.NewReader("")).Reduce("0", sum)
+		require.NoError(t, err)
+		assert.Equal(t, "0", got)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Cat("no-such-file", "testdata/b.txt").Reduce("", func(acc, line string) string { return acc + line })
+		assert.Error(t, err)
+		assert.Equal(t, "bb", got)
+	})
+}
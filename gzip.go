@@ -0,0 +1,63 @@
+package script
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// Gzip compresses the stream using gzip. The gzip trailer is written when the input is done.
+//
+// Shell command: `gzip -c`.
+func (s Stream) Gzip() Stream {
+	return s.Through(encodePipe{
+		name:      "gzip",
+		newWriter: func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+	})
+}
+
+// Gunzip decompresses a gzip compressed stream. Corrupt input results in an error in the output.
+//
+// Shell command: `gunzip -c`.
+func (s Stream) Gunzip() Stream {
+	return s.Through(gunzip{})
+}
+
+type gunzip struct{}
+
+func (gunzip) Pipe(stdin io.Reader) (io.Reader, error) {
+	return &gunzipReader{r: stdin}, nil
+}
+
+func (gunzip) Name() string {
+	return "gunzip"
+}
+
+// gunzipReader decompresses the underlying reader. The gzip header is read lazily, on the first
+// read, such that creating the stream does not block on reading the input.
+type gunzipReader struct {
+	r  io.Reader
+	gz *gzip.Reader
+}
+
+func (g *gunzipReader) Read(b []byte) (int, error) {
+	if g.gz == nil {
+		gz, err := gzip.NewReader(g.r)
+		if err != nil {
+			return 0, fmt.Errorf("gunzip: %w", err)
+		}
+		g.gz = gz
+	}
+	n, err := g.gz.Read(b)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("gunzip: %w", err)
+	}
+	return n, err
+}
+
+func (g *gunzipReader) Close() error {
+	if g.gz == nil {
+		return nil
+	}
+	return g.gz.Close()
+}
@@ -0,0 +1,46 @@
+package script
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGzip(t *testing.T) {
+	t.Parallel()
+
+	t.Run("gzip", func(t *testing.T) {
+		got, err := Echo("hello").Gzip().Bytes()
+		require.NoError(t, err)
+
+		r, err := gzip.NewReader(bytes.NewReader(got))
+		require.NoError(t, err)
+		decompressed, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "hello\n", string(decompressed))
+	})
+
+	t.Run("round trip of long input", func(t *testing.T) {
+		in := strings.Repeat("line of text\n", 100000)
+		got, err := From("test", strings.NewReader(in)).Gzip().Gunzip().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, in, got)
+	})
+
+	t.Run("corrupt input", func(t *testing.T) {
+		_, err := Echo("not gzip").Gunzip().ToString()
+		assert.Error(t, err)
+	})
+
+	t.Run("truncated input", func(t *testing.T) {
+		compressed, err := Echo("hello").Gzip().Bytes()
+		require.NoError(t, err)
+		_, err = From("test", bytes.NewReader(compressed[:len(compressed)-4])).Gunzip().ToString()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,29 @@
+package script
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+)
+
+// SHA256Sum returns the hex encoded SHA-256 digest of the stream content. The content is hashed as
+// it is read, without storing it.
+//
+// Shell command: `sha256sum`.
+func (s Stream) SHA256Sum() (string, error) {
+	return s.hashSum(sha256.New())
+}
+
+// MD5Sum returns the hex encoded MD5 digest of the stream content. The content is hashed as it is
+// read, without storing it.
+//
+// Shell command: `md5sum`.
+func (s Stream) MD5Sum() (string, error) {
+	return s.hashSum(md5.New())
+}
+
+func (s Stream) hashSum(h hash.Hash) (string, error) {
+	_, err := s.to(h)
+	return hex.EncodeToString(h.Sum(nil)), err
+}
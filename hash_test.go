@@ -0,0 +1,29 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashSum(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sha256", func(t *testing.T) {
+		got, err := Cat("testdata/a.txt").SHA256Sum()
+		require.NoError(t, err)
+		assert.Equal(t, "87428fc522803d31065e7bce3cf03fe475096631e5e07bbd7a0fde60c4cf25c7", got)
+	})
+
+	t.Run("md5", func(t *testing.T) {
+		got, err := Cat("testdata/a.txt").MD5Sum()
+		require.NoError(t, err)
+		assert.Equal(t, "60b725f10c9c85c70d97880dfe8191b3", got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := Cat("no-such-file").SHA256Sum()
+		assert.Error(t, err)
+	})
+}
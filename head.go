@@ -28,6 +28,86 @@ func (s Stream) Tail(n int) Stream {
 	return s.Modify(&tail{n: n, lines: make([][]byte, 0, n)})
 }
 
+// First reads only the n first lines of the given reader, and stops reading from the previous
+// stages afterwards. If n is not positive, the stream is empty.
+//
+// Shell command: `head -n <n>`
+func (s Stream) First(n int) Stream {
+	if n < 0 {
+		n = 0
+	}
+	return s.Head(n)
+}
+
+// Last reads only the n last lines of the given reader. The last n lines are buffered in memory.
+// If n is not positive, the stream is empty.
+//
+// Shell command: `tail -n <n>`
+func (s Stream) Last(n int) Stream {
+	if n < 0 {
+		n = 0
+	}
+	return s.Tail(n)
+}
+
+// LastWith reads only the n last lines of the given reader, similar to `Last`, while keeping the
+// total size of the buffered lines, including their line breaks, at most maxBytes. When a line is
+// buffered and the total size exceeds maxBytes, the oldest lines are dropped until it fits, so the
+// output may contain less than n lines, and a single line that is larger than maxBytes is dropped
+// entirely. If maxBytes is not positive, the size is not limited.
+//
+// Shell command: `tail -n <n>`
+func (s Stream) LastWith(n int, maxBytes int64) Stream {
+	if n < 0 {
+		n = 0
+	}
+	return s.Modify(&tail{n: n, lines: make([][]byte, 0, n), maxBytes: maxBytes})
+}
+
+// Skip omits the n first lines of the given reader, and outputs the rest of the lines. If n is not
+// positive, the stream is not changed.
+//
+// Shell command: `tail -n +<n+1>`
+func (s Stream) Skip(n int) Stream {
+	if n <= 0 {
+		return s
+	}
+	return s.Tail(-n)
+}
+
+// DropLast omits the n last lines of the given reader. The last n lines are buffered in memory. If n
+// is not positive, the stream is not changed.
+//
+// Shell command: `head -n -<n>`
+func (s Stream) DropLast(n int) Stream {
+	if n <= 0 {
+		return s
+	}
+	return s.Head(-n)
+}
+
+// HeadBytes reads only the n first bytes of the given reader, and stops reading from the previous
+// stages afterwards. If n is not positive, the stream is empty.
+//
+// Shell command: `head -c <n>`
+func (s Stream) HeadBytes(n int64) Stream {
+	if n < 0 {
+		n = 0
+	}
+	return s.Through(headBytes(n))
+}
+
+// TailBytes reads only the n last bytes of the given reader. The last n bytes are buffered in
+// memory. If n is not positive, the stream is empty.
+//
+// Shell command: `tail -c <n>`
+func (s Stream) TailBytes(n int64) Stream {
+	if n < 0 {
+		n = 0
+	}
+	return s.Through(tailBytes(n))
+}
+
 type head struct {
 	n int
 }
@@ -37,6 +117,10 @@ func (h *head) Modify(line []byte) ([]byte, error) {
 		return nil, io.EOF
 	}
 	h.n--
+	if h.n == 0 {
+		// Stop without waiting for the next line.
+		return append(line, '\n'), io.EOF
+	}
 	return append(line, '\n'), nil
 }
 
@@ -79,6 +163,9 @@ func (h *negHead) Name() string {
 type tail struct {
 	n     int
 	lines [][]byte
+	// maxBytes limits the total size of the lines, if positive. size is the current total size.
+	maxBytes int64
+	size     int64
 }
 
 func (t *tail) Modify(line []byte) ([]byte, error) {
@@ -86,6 +173,9 @@ func (t *tail) Modify(line []byte) ([]byte, error) {
 		return nil, io.EOF
 	}
 	if line == nil {
+		if len(t.lines) == 0 {
+			return nil, io.EOF
+		}
 		return append(bytes.Join(t.lines, []byte{'\n'}), '\n'), io.EOF
 	}
 
@@ -93,11 +183,23 @@ func (t *tail) Modify(line []byte) ([]byte, error) {
 	if len(t.lines) < cap(t.lines) {
 		t.lines = append(t.lines, line)
 	} else {
+		t.size -= int64(len(t.lines[0]) + 1)
 		for i := 0; i < len(t.lines)-1; i++ {
 			t.lines[i] = t.lines[i+1]
 		}
 		t.lines[len(t.lines)-1] = line
 	}
+	t.size += int64(len(line) + 1)
+
+	// Drop the oldest lines until the lines fit in the size limit.
+	if t.maxBytes > 0 {
+		drop := 0
+		for drop < len(t.lines) && t.size > t.maxBytes {
+			t.size -= int64(len(t.lines[drop]) + 1)
+			drop++
+		}
+		t.lines = append(t.lines[:0], t.lines[drop:]...)
+	}
 
 	return nil, nil
 }
@@ -124,3 +226,71 @@ func (t *negTail) Modify(line []byte) ([]byte, error) {
 func (t *negTail) Name() string {
 	return fmt.Sprintf("tail(-%d)", t.n)
 }
+
+type headBytes int64
+
+func (h headBytes) Pipe(stdin io.Reader) (io.Reader, error) {
+	return io.LimitReader(stdin, int64(h)), nil
+}
+
+func (h headBytes) Name() string {
+	return fmt.Sprintf("head-bytes(%d)", h)
+}
+
+type tailBytes int64
+
+func (t tailBytes) Pipe(stdin io.Reader) (io.Reader, error) {
+	return &tailBytesReader{r: stdin, n: int(t)}, nil
+}
+
+func (t tailBytes) Name() string {
+	return fmt.Sprintf("tail-bytes(%d)", t)
+}
+
+// tailBytesReader reads the whole underlying reader on the first read, and then outputs its last n
+// bytes.
+type tailBytesReader struct {
+	r io.Reader
+	n int
+	// last contains the last bytes of the input. Once the input is fully read, it contains at most
+	// n bytes.
+	last []byte
+	done bool
+}
+
+func (t *tailBytesReader) Read(b []byte) (int, error) {
+	if !t.done {
+		if err := t.readAll(); err != nil {
+			return 0, err
+		}
+		t.done = true
+	}
+	if len(t.last) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, t.last)
+	t.last = t.last[n:]
+	return n, nil
+}
+
+func (t *tailBytesReader) readAll() error {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := t.r.Read(buf)
+		t.last = append(t.last, buf[:n]...)
+		// Keep at most twice the required size, to avoid copying on every read.
+		if len(t.last) > 2*t.n {
+			t.last = append(t.last[:0], t.last[len(t.last)-t.n:]...)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(t.last) > t.n {
+		t.last = t.last[len(t.last)-t.n:]
+	}
+	return nil
+}
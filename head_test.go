@@ -2,6 +2,7 @@ package script
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -50,3 +51,163 @@ func TestHeadTail(t *testing.T) {
 		})
 	}
 }
+
+func TestFirstLast(t *testing.T) {
+	t.Parallel()
+
+	const text = "a\nbb\nccc"
+
+	tests := []struct {
+		n     int
+		first string
+		last  string
+	}{
+		{n: -1, first: "", last: ""},
+		{n: 0, first: "", last: ""},
+		{n: 1, first: "a\n", last: "ccc\n"},
+		{n: 2, first: "a\nbb\n", last: "bb\nccc\n"},
+		{n: 4, first: "a\nbb\nccc\n", last: "a\nbb\nccc\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("first/%d", tt.n), func(t *testing.T) {
+			got, err := Echo(text).First(tt.n).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.first, got)
+		})
+		t.Run(fmt.Sprintf("last/%d", tt.n), func(t *testing.T) {
+			got, err := Echo(text).Last(tt.n).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.last, got)
+		})
+	}
+
+	t.Run("last of empty stream", func(t *testing.T) {
+		got, err := From("empty", strings.NewReader("")).Last(2).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+}
+
+func TestLastWith(t *testing.T) {
+	t.Parallel()
+
+	huge := strings.Repeat("x", 100)
+
+	tests := []struct {
+		name string
+		s    Stream
+		want string
+	}{
+		{name: "fits", s: Echo("a\nbb\nccc").LastWith(2, 7), want: "bb\nccc\n"},
+		{name: "drop oldest", s: Echo("a\nbb\nccc").LastWith(3, 7), want: "bb\nccc\n"},
+		{name: "huge line", s: Echo("a\n"+huge+"\nb\nc").LastWith(3, 10), want: "b\nc\n"},
+		{name: "huge last line", s: Echo("a\nb\n"+huge).LastWith(3, 10), want: ""},
+		{name: "unlimited", s: Echo("a\n"+huge).LastWith(2, 0), want: "a\n" + huge + "\n"},
+		{name: "zero lines", s: Echo("a\nb").LastWith(0, 10), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSkipDropLast(t *testing.T) {
+	t.Parallel()
+
+	const text = "a\nbb\nccc"
+
+	tests := []struct {
+		n        int
+		skip     string
+		dropLast string
+	}{
+		{n: -1, skip: "a\nbb\nccc\n", dropLast: "a\nbb\nccc\n"},
+		{n: 0, skip: "a\nbb\nccc\n", dropLast: "a\nbb\nccc\n"},
+		{n: 1, skip: "bb\nccc\n", dropLast: "a\nbb\n"},
+		{n: 2, skip: "ccc\n", dropLast: "a\n"},
+		{n: 4, skip: "", dropLast: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("skip/%d", tt.n), func(t *testing.T) {
+			got, err := Echo(text).Skip(tt.n).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.skip, got)
+		})
+		t.Run(fmt.Sprintf("drop last/%d", tt.n), func(t *testing.T) {
+			got, err := Echo(text).DropLast(tt.n).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.dropLast, got)
+		})
+	}
+}
+
+func TestHeadTailBytes(t *testing.T) {
+	t.Parallel()
+
+	const text = "abcdef"
+
+	tests := []struct {
+		n    int64
+		head string
+		tail string
+	}{
+		{n: -1, head: "", tail: ""},
+		{n: 0, head: "", tail: ""},
+		{n: 2, head: "ab", tail: "ef"},
+		{n: 6, head: "abcdef", tail: "abcdef"},
+		{n: 10, head: "abcdef", tail: "abcdef"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("head/%d", tt.n), func(t *testing.T) {
+			got, err := From("text", strings.NewReader(text)).HeadBytes(tt.n).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.head, got)
+		})
+		t.Run(fmt.Sprintf("tail/%d", tt.n), func(t *testing.T) {
+			got, err := From("text", strings.NewReader(text)).TailBytes(tt.n).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.tail, got)
+		})
+	}
+
+	t.Run("head stops reading", func(t *testing.T) {
+		r := strings.NewReader(strings.Repeat("a", 1000))
+		got, err := From("text", r).HeadBytes(10).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "aaaaaaaaaa", got)
+		assert.Equal(t, 990, r.Len())
+	})
+
+	t.Run("tail of long input", func(t *testing.T) {
+		in := strings.Repeat("0123456789", 10000)
+		got, err := From("text", strings.NewReader(in)).TailBytes(15).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "567890123456789", got)
+	})
+}
+
+func TestHeadTail_manyLines(t *testing.T) {
+	t.Parallel()
+
+	// Create enough lines such that the buffered lines are not in the same read buffer.
+	var lines []string
+	for i := 0; i < 2000; i++ {
+		lines = append(lines, fmt.Sprintf("line-%04d", i))
+	}
+	text := strings.Join(lines, "\n")
+
+	got, err := Echo(text).Tail(1000).ToString()
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join(lines[1000:], "\n")+"\n", got)
+
+	got, err = Echo(text).Head(-1000).ToString()
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join(lines[:1000], "\n")+"\n", got)
+}
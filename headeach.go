@@ -0,0 +1,91 @@
+package script
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// HeadEach outputs the n first lines of each of the files, in order. The lines of each file are
+// preceded by a `==> <path> <==` header line, and files are separated by an empty line. The files
+// are opened one after the other, as the stream is read, and only n lines are stored in memory. If
+// a file fails to be read, it results in an error in the output, and the file is omitted.
+//
+// It is not named `Head`, since `Files` already has the `Head` method of its stream, that outputs
+// the n first paths.
+//
+// Shell command: `head -n <n> <files>`.
+func (f Files) HeadEach(n int) Stream {
+	if n < 0 {
+		n = 0
+	}
+	return f.stream(fmt.Sprintf("head-each(%d)", n), &headEachReader{files: f.Files, n: n})
+}
+
+// headEachReader reads the first lines of each file, one file after the other.
+type headEachReader struct {
+	files []FileInfo
+	n     int
+	// buf stores the output of the current file that was not read yet.
+	buf bytes.Buffer
+	// written indicates if any file was already output.
+	written bool
+	errors  *multierror.Error
+}
+
+func (h *headEachReader) Read(b []byte) (int, error) {
+	for h.buf.Len() == 0 {
+		if len(h.files) == 0 {
+			return 0, io.EOF
+		}
+		path := h.files[0].Path
+		h.files = h.files[1:]
+		if err := h.head(path); err != nil {
+			h.errors = multierror.Append(h.errors, err)
+		}
+	}
+	return h.buf.Read(b)
+}
+
+// head writes the header and the first lines of the given file to the buffer.
+func (h *headEachReader) head(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return pathError("open path", path, err)
+	}
+	defer f.Close()
+
+	var lines bytes.Buffer
+	r := bufio.NewReader(f)
+	for i := 0; i < h.n; i++ {
+		line, err := r.ReadBytes('\n')
+		lines.Write(line)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return pathError("read path", path, err)
+		}
+	}
+	// Complete the last line of a file that does not end with a line break.
+	if lines.Len() > 0 && lines.Bytes()[lines.Len()-1] != '\n' {
+		lines.WriteByte('\n')
+	}
+
+	if h.written {
+		h.buf.WriteByte('\n')
+	}
+	h.written = true
+	fmt.Fprintf(&h.buf, "==> %s <==\n", path)
+	h.buf.Write(lines.Bytes())
+	return nil
+}
+
+// Close returns the errors of files that failed to be read.
+func (h *headEachReader) Close() error {
+	return h.errors.ErrorOrNil()
+}
@@ -0,0 +1,52 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesHeadEach(t *testing.T) {
+	t.Parallel()
+
+	long := tempFile(t, "1\n2\n3\n4")
+	defer os.RemoveAll(filepath.Dir(long))
+
+	t.Run("head", func(t *testing.T) {
+		got, err := Ls(long, "testdata").HeadEach(2).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "==> "+long+" <==\n1\n2\n\n==> testdata/a.txt <==\na\n\n==> testdata/b.txt <==\nbb\n", got)
+	})
+
+	t.Run("no final line break", func(t *testing.T) {
+		got, err := Ls(long).HeadEach(10).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "==> "+long+" <==\n1\n2\n3\n4\n", got)
+	})
+
+	t.Run("zero lines", func(t *testing.T) {
+		got, err := Ls("testdata").HeadEach(0).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "==> testdata/a.txt <==\n\n==> testdata/b.txt <==\n", got)
+	})
+
+	t.Run("stream head is not changed", func(t *testing.T) {
+		got, err := Ls("testdata").Head(1).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "testdata/a.txt\n", got)
+	})
+
+	t.Run("unreadable file", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+		files := Ls(filepath.Join(dir, "a.txt"), "testdata/b.txt")
+		require.NoError(t, os.Remove(filepath.Join(dir, "a.txt")))
+
+		got, err := files.HeadEach(1).ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "==> testdata/b.txt <==\nbb\n", got)
+	})
+}
@@ -0,0 +1,128 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Get performs an HTTP GET request to the given URL and streams the response body. The request is
+// performed on the first read from the stream, such that it can be canceled by a following
+// `WithContext` or `WithTimeout` stage. Transport errors and non-2xx status codes are returned by
+// the final terminal method, such as `To` or `Close`. The body of a non-2xx response is still
+// streamed. The body is closed once it is fully read, when reading it fails, or when the stream is
+// closed.
+//
+// Shell command: `curl <url>`.
+func Get(url string) Stream {
+	return GetWith(http.DefaultClient, url)
+}
+
+// GetWith performs an HTTP GET request to the given URL using the given client, and streams the
+// response body. See `Get` for more details.
+func GetWith(client *http.Client, url string) Stream {
+	return Stream{stage: fmt.Sprintf("get(%s)", url), r: &bodyReader{client: client, url: url}}
+}
+
+// Post sends the stream as the body of an HTTP POST request to the given URL, and closes the
+// stream. The body is streamed, and not loaded to memory. The returned error contains the request
+// error together with the errors of the stream. If the request was sent, the response is returned
+// even if the stream failed, and the caller should close its body.
+//
+// Shell command: `curl -X POST -H "Content-Type: <contentType>" --data-binary @- <url>`.
+func (s Stream) Post(url, contentType string) (*http.Response, error) {
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var errors *multierror.Error
+	// The stream is wrapped such that the client does not close it, in order to collect its
+	// errors after the request is done.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, struct{ io.Reader }{s})
+	if err != nil {
+		errors = multierror.Append(errors, fmt.Errorf("post %s: %w", url, err))
+		if err := s.Close(); err != nil {
+			errors = multierror.Append(errors, err)
+		}
+		return nil, errors.ErrorOrNil()
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		errors = multierror.Append(errors, fmt.Errorf("post %s: %w", url, err))
+	}
+	if err := s.Close(); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	return resp, errors.ErrorOrNil()
+}
+
+// bodyReader performs an HTTP GET request on the first read, and reads the response body. The body
+// is closed when reading it is done.
+type bodyReader struct {
+	client *http.Client
+	url    string
+	// ctx is the context of the request, if one was set by a following stage.
+	ctx  context.Context
+	body io.ReadCloser
+	// started indicates if the request was performed.
+	started bool
+	// closed indicates if the body was already closed.
+	closed bool
+	errors *multierror.Error
+}
+
+func (r *bodyReader) Read(b []byte) (int, error) {
+	if !r.started {
+		r.started = true
+		r.get()
+	}
+	if r.closed || r.body == nil {
+		return 0, io.EOF
+	}
+	n, err := r.body.Read(b)
+	if err != nil {
+		r.Close()
+	}
+	return n, err
+}
+
+// get performs the request. Its errors are returned when the reader is closed.
+func (r *bodyReader) get() {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		r.errors = multierror.Append(r.errors, fmt.Errorf("get %s: %w", r.url, err))
+		return
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		r.errors = multierror.Append(r.errors, fmt.Errorf("get %s: %w", r.url, err))
+		return
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		r.errors = multierror.Append(r.errors, fmt.Errorf("get %s: status %s", r.url, resp.Status))
+	}
+	r.body = resp.Body
+}
+
+func (r *bodyReader) setContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+func (r *bodyReader) Close() error {
+	if r.body != nil && !r.closed {
+		r.closed = true
+		if err := r.body.Close(); err != nil {
+			r.errors = multierror.Append(r.errors, fmt.Errorf("close body: %w", err))
+		}
+	}
+	return r.errors.ErrorOrNil()
+}
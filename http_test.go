@@ -0,0 +1,98 @@
+package script
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			fmt.Fprint(w, "a\nb\n")
+		case "/slow":
+			time.Sleep(100 * time.Millisecond)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("get", func(t *testing.T) {
+		s := Get(server.URL + "/ok")
+		require.NoError(t, s.Error())
+		got, err := s.Grep("b").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "b\n", got)
+	})
+
+	t.Run("status error", func(t *testing.T) {
+		got, err := Get(server.URL + "/no-such-path").ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "not found\n", got)
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		got, err := Get("http://127.0.0.1:0/").ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "", got)
+	})
+
+	t.Run("request on read", func(t *testing.T) {
+		s := Get("http://127.0.0.1:0/")
+		// The request was not performed yet.
+		assert.NoError(t, s.Error())
+		assert.NoError(t, s.Close())
+	})
+
+	t.Run("client", func(t *testing.T) {
+		client := &http.Client{Timeout: 10 * time.Millisecond}
+		_, err := GetWith(client, server.URL+"/slow").ToString()
+		assert.Error(t, err)
+	})
+}
+
+func TestPost(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %s", r.Header.Get("Content-Type"), body)
+	}))
+	defer server.Close()
+
+	t.Run("post", func(t *testing.T) {
+		resp, err := Echo("a\nb\nc").Grep("b").Post(server.URL, "text/plain")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		got, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain b\n", string(got))
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		resp, err := Cat("no-such-file", "testdata/a.txt").Post(server.URL, "text/plain")
+		assert.Error(t, err)
+		require.NotNil(t, resp)
+		defer resp.Body.Close()
+		got, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain a\n", string(got))
+	})
+
+	t.Run("request error", func(t *testing.T) {
+		resp, err := Echo("a").Post("http://127.0.0.1:0/", "text/plain")
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
@@ -0,0 +1,37 @@
+package script
+
+import (
+	"fmt"
+)
+
+// Join joins all the lines of the stream to a single line, separated by the given separator. An
+// empty stream results in an empty output.
+//
+// Shell command: `paste -s -d<sep>`.
+func (s Stream) Join(sep string) Stream {
+	return s.Modify(&join{sep: []byte(sep)})
+}
+
+type join struct {
+	sep []byte
+	// started indicates if a line was already written.
+	started bool
+}
+
+func (j *join) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		if !j.started {
+			return nil, nil
+		}
+		return []byte{'\n'}, nil
+	}
+	if !j.started {
+		j.started = true
+		return line, nil
+	}
+	return append(append([]byte(nil), j.sep...), line...), nil
+}
+
+func (j *join) Name() string {
+	return fmt.Sprintf("join(%q)", j.sep)
+}
@@ -0,0 +1,31 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("join", func(t *testing.T) {
+		got, err := Ls("testdata").Join(" ").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "testdata/a.txt testdata/b.txt\n", got)
+	})
+
+	t.Run("single line", func(t *testing.T) {
+		got, err := Echo("a").Join(", ").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\n", got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := From("empty", strings.NewReader("")).Join(", ").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+}
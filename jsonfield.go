@@ -0,0 +1,109 @@
+package script
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// maxJSONErrors is the maximum number of parse errors that `JSONField` records individually.
+const maxJSONErrors = 10
+
+// JSONField parses each line as a JSON value and takes the value at the given dotted path, such
+// as `user.id`. Path parts are object keys, or indices for arrays, such that `items.0.name` is the
+// name of the first item. An empty path takes the whole value. Strings are output without quotes,
+// and other values are output in compact JSON encoding. Lines that do not have the path are
+// omitted.
+//
+// Lines that fail to be parsed result in an error in the output and are omitted. Only the first 10
+// parse errors are recorded, and the number of the other lines that failed is added as a single
+// error.
+//
+// Shell command: `jq -r '.<path>'`.
+func (s Stream) JSONField(path string) Stream {
+	var parts []string
+	if path != "" {
+		parts = strings.Split(path, ".")
+	}
+	return s.Modify(&jsonField{path: path, parts: parts})
+}
+
+// jsonField is a modifier that takes a single value from each JSON line.
+type jsonField struct {
+	path  string
+	parts []string
+	// errors are the recorded parse errors, and failed is the number of lines that failed.
+	errors *multierror.Error
+	failed int
+}
+
+func (j *jsonField) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	if len(bytes.TrimSpace(line)) == 0 {
+		return nil, nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(line))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		j.failed++
+		if j.failed <= maxJSONErrors {
+			j.errors = multierror.Append(j.errors, fmt.Errorf("parse json line %q: %w", line, err))
+		}
+		return nil, nil
+	}
+
+	v, ok := jsonLookup(v, j.parts)
+	if !ok {
+		return nil, nil
+	}
+	if str, ok := v.(string); ok {
+		return append([]byte(str), '\n'), nil
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode json value: %w", err)
+	}
+	return append(out, '\n'), nil
+}
+
+// jsonLookup returns the value at the given path parts in a decoded JSON value.
+func jsonLookup(v interface{}, parts []string) (interface{}, bool) {
+	for _, part := range parts {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = value[part]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(value) {
+				return nil, false
+			}
+			v = value[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func (j *jsonField) Close() error {
+	errors := j.errors
+	if j.failed > maxJSONErrors {
+		errors = multierror.Append(errors, fmt.Errorf("parse json: %d more lines failed", j.failed-maxJSONErrors))
+	}
+	return errors.ErrorOrNil()
+}
+
+func (j *jsonField) Name() string {
+	return fmt.Sprintf("json-field(%s)", j.path)
+}
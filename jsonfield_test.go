@@ -0,0 +1,72 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONField(t *testing.T) {
+	t.Parallel()
+
+	const events = `{"user": {"id": 1, "name": "a"}, "items": [{"name": "x"}, {"name": "y"}], "ok": true}
+{"user": {"id": 2.5}, "items": [], "error": {"code": "E1"}, "ok": null}
+{"user": "b", "error": {"code": "E2"}}
+
+{"error": {"code": "E1", "details": {"a": [1, 2]}}}`
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "user.id", want: "1\n2.5\n"},
+		{path: "user.name", want: "a\n"},
+		{path: "items.0.name", want: "x\n"},
+		{path: "items.1", want: "{\"name\":\"y\"}\n"},
+		{path: "items.2", want: ""},
+		{path: "items.x", want: ""},
+		{path: "ok", want: "true\nnull\n"},
+		{path: "error.details", want: "{\"a\":[1,2]}\n"},
+		{path: "user", want: "{\"id\":1,\"name\":\"a\"}\n{\"id\":2.5}\nb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := Echo(events).JSONField(tt.path).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("whole value", func(t *testing.T) {
+		got, err := Echo(`"a"`, "\n", `[1, 2]`).JSONField("").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\n[1,2]\n", got)
+	})
+
+	t.Run("freq", func(t *testing.T) {
+		got, err := Echo(events).JSONField("error.code").Freq().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "   2 E1\n   1 E2\n", got)
+	})
+
+	t.Run("parse errors", func(t *testing.T) {
+		got, err := Echo(`{"a": 1}`, "\n{bad\n", `{"a": 2}`).JSONField("a").ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "1\n2\n", got)
+	})
+
+	t.Run("bounded errors", func(t *testing.T) {
+		in := strings.Repeat("bad\n", 25) + `{"a": 1}`
+		got, err := Echo(in).JSONField("a").ToString()
+		require.Error(t, err)
+		assert.Equal(t, "1\n", got)
+		merr, ok := err.(*multierror.Error)
+		require.True(t, ok)
+		assert.Len(t, merr.Errors, maxJSONErrors+1)
+		assert.Contains(t, err.Error(), "15 more lines failed")
+	})
+}
@@ -0,0 +1,78 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrLimitExceeded is the error of a stream that is longer than the limit of `LimitBytes`.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// LimitBytes reads at most max bytes of the stream, like `HeadBytes`, but results in an error if
+// the stream is longer than max bytes, instead of truncating it silently. The error wraps
+// `ErrLimitExceeded`. It is useful for protecting from unexpectedly large inputs, such as untrusted
+// downloads. If max is negative, it is considered as zero.
+func (s Stream) LimitBytes(max int64) Stream {
+	if max < 0 {
+		max = 0
+	}
+	return s.Through(limitBytes(max))
+}
+
+type limitBytes int64
+
+func (l limitBytes) Pipe(stdin io.Reader) (io.Reader, error) {
+	return &limitBytesReader{r: stdin, max: int64(l), left: int64(l)}, nil
+}
+
+func (l limitBytes) Name() string {
+	return fmt.Sprintf("limit-bytes(%d)", l)
+}
+
+// limitBytesReader reads up to max bytes, and checks if the underlying reader has more bytes once
+// the limit is reached.
+type limitBytesReader struct {
+	r         io.Reader
+	max, left int64
+	done      bool
+	exceeded  bool
+}
+
+func (l *limitBytesReader) Read(b []byte) (int, error) {
+	if l.done {
+		return 0, io.EOF
+	}
+	if l.left <= 0 {
+		// Check if there is any more content, without outputting it.
+		var extra [1]byte
+		for {
+			n, err := l.r.Read(extra[:])
+			if n > 0 {
+				l.exceeded = true
+			}
+			if n > 0 || err != nil {
+				break
+			}
+		}
+		l.done = true
+		return 0, io.EOF
+	}
+	if int64(len(b)) > l.left {
+		b = b[:l.left]
+	}
+	n, err := l.r.Read(b)
+	l.left -= int64(n)
+	if err != nil {
+		l.done = true
+	}
+	return n, err
+}
+
+// Close returns an error if the stream exceeded the limit.
+func (l *limitBytesReader) Close() error {
+	if l.exceeded {
+		return fmt.Errorf("stream is longer than %d bytes: %w", l.max, ErrLimitExceeded)
+	}
+	return nil
+}
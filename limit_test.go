@@ -0,0 +1,62 @@
+package script
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		in       string
+		max      int64
+		want     string
+		exceeded bool
+	}{
+		{name: "shorter", in: "abc", max: 5, want: "abc"},
+		{name: "exact", in: "abc", max: 3, want: "abc"},
+		{name: "longer", in: "abcdef", max: 3, want: "abc", exceeded: true},
+		{name: "zero", in: "a", max: 0, want: "", exceeded: true},
+		{name: "negative", in: "a", max: -1, want: "", exceeded: true},
+		{name: "empty", in: "", max: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, r := range []struct {
+				name string
+				s    Stream
+			}{
+				{name: "reader", s: From("in", strings.NewReader(tt.in))},
+				{name: "one byte reader", s: From("in", iotest.OneByteReader(strings.NewReader(tt.in)))},
+			} {
+				got, err := r.s.LimitBytes(tt.max).ToString()
+				assert.Equal(t, tt.want, got, r.name)
+				if tt.exceeded {
+					assert.True(t, errors.Is(err, ErrLimitExceeded), "%s: %v", r.name, err)
+				} else {
+					assert.NoError(t, err, r.name)
+				}
+			}
+		})
+	}
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Cat("testdata/a.txt", "no-such-file").LimitBytes(10).ToString()
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, ErrLimitExceeded))
+		assert.Equal(t, "a\n", got)
+	})
+
+	t.Run("following stages", func(t *testing.T) {
+		got, err := Echo("a\nb\nc").LimitBytes(4).Slice()
+		assert.True(t, errors.Is(err, ErrLimitExceeded))
+		assert.Equal(t, []string{"a", "b"}, got)
+	})
+}
@@ -2,11 +2,13 @@ package script
 
 import (
 	"fmt"
-	"github.com/hashicorp/go-multierror"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
 )
 
 // Files is a stream of a list of files. A user can either use the file list directly or the the
@@ -23,6 +25,39 @@ type FileInfo struct {
 	// Path is the path of the file. It may be relative or absolute, depending on how the `Ls`
 	// command was invoked.
 	Path string
+	// Depth is the number of directories between the listed path and the file, for recursive
+	// listing. Entries of a listed directory have a depth of 1, and a listed file has a depth of 0.
+	// It is always 0 for non-recursive listing.
+	Depth int
+}
+
+// Abs returns the absolute path of the file. It does not modify the stored path.
+func (f FileInfo) Abs() (string, error) {
+	return filepath.Abs(f.Path)
+}
+
+// Rel returns the path of the file relative to the given base directory. It does not modify the
+// stored path.
+func (f FileInfo) Rel(base string) (string, error) {
+	abs, err := f.Abs()
+	if err != nil {
+		return "", err
+	}
+	base, err = filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(base, abs)
+}
+
+// SymlinkTarget returns the target of the file if it is a symbolic link, and an error otherwise.
+// The target is returned as it is stored in the link: a relative target is relative to the
+// directory of the link, and is not resolved.
+func (f FileInfo) SymlinkTarget() (string, error) {
+	if f.Mode()&os.ModeSymlink == 0 {
+		return "", fmt.Errorf("path %s is not a symbolic link", f.Path)
+	}
+	return os.Readlink(f.Path)
 }
 
 // Ls returns a stream of a list files. In the returned stream, each line will contain a path to
@@ -33,14 +68,74 @@ type FileInfo struct {
 // The provided paths may be relative to the local directory or absolute - this will influence the
 // format of the returned paths in the output.
 //
+// The provided paths may contain glob patterns, such as `*.go`, which are expanded to the matching
+// paths. A pattern that does not match any path results in an error.
+//
 // If some provided paths correlate to the arguments correlate to the same file, it will also appear
 // multiple times in the output.
 //
 // If any of the paths fails to be listed, it will result in an error in the output, but the stream
 // will still conain all paths that were successfully listed.
 //
-// Shell command: `ls`.
+// Shell command: `ls -a`.
 func Ls(paths ...string) Files {
+	return LsWith(LsOptions{IncludeHidden: true}, paths...)
+}
+
+// Stat returns the information of a single file, without creating a stream. Symbolic links are
+// followed.
+//
+// Shell command: `stat <path>`.
+func Stat(path string) (FileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return FileInfo{}, pathError("stat path", path, err)
+	}
+	return FileInfo{FileInfo: info, Path: path}, nil
+}
+
+// LsOptions are options for listing files.
+type LsOptions struct {
+	// IncludeHidden includes files in listed directories that their name starts with a dot. Paths
+	// that were explicitly given are always listed.
+	IncludeHidden bool
+	// FollowSymlinks resolves symbolic links in listed directories, such that the file information
+	// describes the link target instead of the link itself. Broken links result in an error and
+	// are omitted from the output.
+	FollowSymlinks bool
+	// MaxDepth limits the depth of directories that are walked by `LsRecursiveWith`, relative to
+	// each of the given paths. Entries of a given directory have a depth of 1. Entries that are
+	// deeper are skipped. Zero means unlimited depth.
+	MaxDepth int
+	// Absolute converts all the paths to absolute paths, regardless of the form of the given paths.
+	// If a path fails to be converted, it results in an error and the path is kept as is.
+	Absolute bool
+	// FilesOnly lists only files that are not directories, both for the given paths and for the
+	// entries of listed directories. Used by `LsWith`, since `LsRecursiveWith` always lists only
+	// files. Symbolic links are listed as files, unless `FollowSymlinks` resolves them to a
+	// directory.
+	FilesOnly bool
+	// DirsOnly lists only directories, both for the given paths and for the entries of listed
+	// directories. Used by `LsWith`. Setting both `FilesOnly` and `DirsOnly` lists nothing.
+	DirsOnly bool
+}
+
+// keepType returns true if a file with the given information should be listed according to the
+// `FilesOnly` and `DirsOnly` options.
+func (opts LsOptions) keepType(info os.FileInfo) bool {
+	if opts.FilesOnly && info.IsDir() {
+		return false
+	}
+	if opts.DirsOnly && !info.IsDir() {
+		return false
+	}
+	return true
+}
+
+// LsWith returns a stream of a list files, similar to `Ls`, according to the given options.
+//
+// Shell command: `ls`.
+func LsWith(opts LsOptions, paths ...string) Files {
 	// Default to local directory.
 	if len(paths) == 0 {
 		paths = append(paths, ".")
@@ -51,36 +146,217 @@ func Ls(paths ...string) Files {
 		errors *multierror.Error
 	)
 
-	for _, path := range paths {
-		info, err := os.Stat(path)
+	expanded, err := expandGlobs(paths)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	for _, path := range expanded {
+		file, err := Stat(path)
 		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("stat path: %s", err))
+			errors = multierror.Append(errors, err)
 			continue
 		}
 
 		// Path is a single file.
-		if !info.IsDir() {
-			files = append(files, FileInfo{Path: path, FileInfo: info})
+		if !file.IsDir() {
+			if opts.keepType(file) {
+				files = append(files, file)
+			}
 			continue
 		}
 
 		// Path is a directory.
 		infos, err := ioutil.ReadDir(path)
 		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("read dir: %s", err))
+			errors = multierror.Append(errors, pathError("read dir", path, err))
 			continue
 		}
 
 		for _, info := range infos {
-			files = append(files, FileInfo{Path: filepath.Join(path, info.Name()), FileInfo: info})
+			if !opts.IncludeHidden && isHidden(info.Name()) {
+				continue
+			}
+			entryPath := filepath.Join(path, info.Name())
+			if opts.FollowSymlinks && info.Mode()&os.ModeSymlink != 0 {
+				info, err = os.Stat(entryPath)
+				if err != nil {
+					errors = multierror.Append(errors, pathError("follow symlink", entryPath, err))
+					continue
+				}
+			}
+			if !opts.keepType(info) {
+				continue
+			}
+			files = append(files, FileInfo{Path: entryPath, FileInfo: info})
+		}
+	}
+
+	if opts.Absolute {
+		if err := absPaths(files); err != nil {
+			errors = multierror.Append(errors, err)
 		}
 	}
 
+	return newFiles(fmt.Sprintf("ls (%+v)", paths), files, errors.ErrorOrNil())
+}
+
+// LsRecursive returns a stream of a list of files, similar to `Ls`, but walks all the directories
+// in the provided paths recursively. Only non-directory files are listed, directories themselves
+// are not part of the output.
+//
+// The provided paths may be relative to the local directory or absolute - this will influence the
+// format of the returned paths in the output.
+//
+// If any of the sub directories fails to be listed, it will result in an error in the output, but
+// the walk will continue and the stream will still contain all paths that were successfully
+// listed.
+//
+// Shell command: `find <paths> -type f`.
+func LsRecursive(paths ...string) Files {
+	return LsRecursiveWith(LsOptions{IncludeHidden: true}, paths...)
+}
+
+// LsRecursiveWith returns a stream of a list of files, similar to `LsRecursive`, according to the
+// given options. When hidden files are not included, hidden directories are not walked.
+//
+// Shell command: `find <paths> [-maxdepth <MaxDepth>] -type f`.
+func LsRecursiveWith(opts LsOptions, paths ...string) Files {
+	// Default to local directory.
+	if len(paths) == 0 {
+		paths = append(paths, ".")
+	}
+
+	var (
+		files  []FileInfo
+		errors *multierror.Error
+	)
+
+	expanded, err := expandGlobs(paths)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	for _, root := range expanded {
+		filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				errors = multierror.Append(errors, pathError("walk path", path, err))
+				return nil
+			}
+			isRoot := path == root
+			if !isRoot && !opts.IncludeHidden && isHidden(info.Name()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info.IsDir() {
+				if !isRoot && opts.MaxDepth > 0 && depth(root, path) >= opts.MaxDepth {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !isRoot && opts.FollowSymlinks && info.Mode()&os.ModeSymlink != 0 {
+				info, err = os.Stat(path)
+				if err != nil {
+					errors = multierror.Append(errors, pathError("follow symlink", path, err))
+					return nil
+				}
+				if info.IsDir() {
+					return nil
+				}
+			}
+			files = append(files, FileInfo{Path: path, FileInfo: info, Depth: depth(root, path)})
+			return nil
+		})
+	}
+
+	if opts.Absolute {
+		if err := absPaths(files); err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	}
+
+	return newFiles(fmt.Sprintf("ls -R (%+v)", paths), files, errors.ErrorOrNil())
+}
+
+// absPaths converts the paths of the given files to absolute paths, in place.
+func absPaths(files []FileInfo) error {
+	var errors *multierror.Error
+	for i := range files {
+		abs, err := files[i].Abs()
+		if err != nil {
+			errors = multierror.Append(errors, pathError("absolute path", files[i].Path, err))
+			continue
+		}
+		files[i].Path = abs
+	}
+	return errors.ErrorOrNil()
+}
+
+// depth returns the number of directories between root and a path inside it. Entries of root have
+// a depth of 1.
+func depth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// FindFiles walks the root directory recursively, similar to `LsRecursive`, and returns only the
+// files that their base name matches the given glob pattern. The pattern syntax is the one of
+// `filepath.Match`.
+//
+// Shell command: `find <root> -type f -name <pattern>`.
+func FindFiles(root, pattern string) Files {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return newFiles(fmt.Sprintf("find (%s, %s)", root, pattern), nil, fmt.Errorf("pattern %q: %w", pattern, err))
+	}
+	return LsRecursive(root).Filter(func(f FileInfo) bool {
+		match, _ := filepath.Match(pattern, f.Name())
+		return match
+	})
+}
+
+// expandGlobs replaces paths that contain glob patterns with the paths that they match. Patterns
+// that do not match any path result in an error.
+func expandGlobs(paths []string) ([]string, error) {
+	var (
+		expanded []string
+		errors   *multierror.Error
+	)
+	for _, path := range paths {
+		if !strings.ContainsAny(path, "*?[") {
+			expanded = append(expanded, path)
+			continue
+		}
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			errors = multierror.Append(errors, pathError("glob pattern", path, err))
+			continue
+		}
+		if len(matches) == 0 {
+			errors = multierror.Append(errors, pathError("glob pattern", path, fmt.Errorf("no matches")))
+			continue
+		}
+		expanded = append(expanded, matches...)
+	}
+	return expanded, errors.ErrorOrNil()
+}
+
+// isHidden returns true if a file name is of a hidden file.
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+// newFiles returns a files object with a stream of the given files list.
+func newFiles(stage string, files []FileInfo, err error) Files {
 	return Files{
 		Stream: Stream{
-			stage: fmt.Sprintf("ls (%+v)", paths),
+			stage: stage,
 			r:     &filesReader{files: files},
-			err:   errors.ErrorOrNil(),
+			err:   err,
 		},
 		Files: files,
 	}
@@ -91,16 +367,19 @@ type filesReader struct {
 	files []FileInfo
 	// seek indicates which file to write for the next Read function call.
 	seek int
+	// partial stores leftover of a line that was not fully read by output.
+	partial []byte
 }
 
-func (f *filesReader) Read(out []byte) (int, error) {
-	if f.seek >= len(f.files) {
-		return 0, io.EOF
+func (f *filesReader) Read(out []byte) (n int, err error) {
+	if len(f.partial) == 0 {
+		if f.seek >= len(f.files) {
+			return 0, io.EOF
+		}
+		f.partial = []byte(f.files[f.seek].Path + "\n")
+		f.seek++
 	}
 
-	line := []byte(f.files[f.seek].Path + "\n")
-	f.seek++
-
-	n := copy(out, line)
+	f.partial, n = copyBytes(out, f.partial)
 	return n, nil
 }
@@ -1,6 +1,10 @@
 package script
 
 import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,6 +35,28 @@ func TestLs(t *testing.T) {
 			paths: []string{"testdata", "testdata/a.txt"},
 			want:  "testdata/a.txt\ntestdata/b.txt\ntestdata/a.txt\n",
 		},
+		{
+			name:  "glob",
+			paths: []string{"testdata/*.txt"},
+			want:  "testdata/a.txt\ntestdata/b.txt\n",
+		},
+		{
+			name:  "glob and literal",
+			paths: []string{"testdata/b.*", "testdata/a.txt"},
+			want:  "testdata/b.txt\ntestdata/a.txt\n",
+		},
+		{
+			name:      "glob without matches",
+			paths:     []string{"testdata/*.go", "testdata/a.txt"},
+			wantError: true,
+			want:      "testdata/a.txt\n",
+		},
+		{
+			name:      "invalid glob",
+			paths:     []string{"testdata/[", "testdata/a.txt"},
+			wantError: true,
+			want:      "testdata/a.txt\n",
+		},
 		{
 			name:      "error",
 			paths:     []string{"no-such-file"},
@@ -53,3 +79,392 @@ func TestLs(t *testing.T) {
 		})
 	}
 }
+
+func TestLs_smallReadBuffer(t *testing.T) {
+	t.Parallel()
+
+	files := Ls("testdata")
+	defer files.Close()
+
+	var got []byte
+	buf := make([]byte, 8)
+	for {
+		n, err := files.Read(buf)
+		got = append(got, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	assert.Equal(t, "testdata/a.txt\ntestdata/b.txt\n", string(got))
+}
+
+func TestLsWith_hidden(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".hidden"), nil, 0664))
+
+	t.Run("exclude hidden", func(t *testing.T) {
+		got, err := LsWith(LsOptions{}, dir).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"a.txt", "b"}), got)
+	})
+
+	t.Run("include hidden", func(t *testing.T) {
+		got, err := LsWith(LsOptions{IncludeHidden: true}, dir).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{".hidden", "a.txt", "b"}), got)
+	})
+
+	t.Run("explicit hidden path", func(t *testing.T) {
+		got, err := LsWith(LsOptions{}, filepath.Join(dir, ".hidden")).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{".hidden"}), got)
+	})
+}
+
+func TestLsWith_type(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+	b := filepath.Join(dir, "b")
+	dirLink := filepath.Join(b, "link")
+	require.NoError(t, os.Symlink(filepath.Join(b, "d"), dirLink))
+
+	tests := []struct {
+		name string
+		opts LsOptions
+		want []string
+	}{
+		{name: "all", opts: LsOptions{}, want: []string{"a.txt", "b/c.txt", "b/d", "b/link"}},
+		{name: "files only", opts: LsOptions{FilesOnly: true}, want: []string{"a.txt", "b/c.txt", "b/link"}},
+		{name: "dirs only", opts: LsOptions{DirsOnly: true}, want: []string{"b/d"}},
+		{name: "dirs only follow symlinks", opts: LsOptions{DirsOnly: true, FollowSymlinks: true}, want: []string{"b/d", "b/link"}},
+		{name: "both", opts: LsOptions{FilesOnly: true, DirsOnly: true}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LsWith(tt.opts, filepath.Join(dir, "a.txt"), b)
+			lines, err := got.Slice()
+			require.NoError(t, err)
+			if tt.want == nil {
+				assert.Empty(t, lines)
+				assert.Empty(t, got.Files)
+				return
+			}
+			assert.Equal(t, inDir(dir, tt.want), lines)
+			assert.Equal(t, inDir(dir, tt.want), paths(got))
+		})
+	}
+}
+
+func TestLsWith_followSymlinks(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+	link := filepath.Join(dir, "b", "link")
+	require.NoError(t, os.Symlink(filepath.Join(dir, "a.txt"), link))
+
+	t.Run("do not follow", func(t *testing.T) {
+		files := LsWith(LsOptions{}, filepath.Join(dir, "b"))
+		require.NoError(t, files.Close())
+		require.Len(t, files.Files, 3)
+		assert.Equal(t, link, files.Files[2].Path)
+		assert.True(t, files.Files[2].Mode()&os.ModeSymlink != 0)
+	})
+
+	t.Run("follow", func(t *testing.T) {
+		files := LsWith(LsOptions{FollowSymlinks: true}, filepath.Join(dir, "b"))
+		require.NoError(t, files.Close())
+		require.Len(t, files.Files, 3)
+		assert.Equal(t, link, files.Files[2].Path)
+		assert.True(t, files.Files[2].Mode().IsRegular())
+		assert.Equal(t, int64(len(filepath.Join(dir, "a.txt"))+1), files.Files[2].Size())
+	})
+
+	t.Run("broken link", func(t *testing.T) {
+		broken := filepath.Join(dir, "b", "d", "broken")
+		require.NoError(t, os.Symlink(filepath.Join(dir, "no-such-file"), broken))
+		got, err := LsWith(LsOptions{FollowSymlinks: true}, filepath.Join(dir, "b", "d")).Slice()
+		assert.Error(t, err)
+		assert.Equal(t, inDir(dir, []string{"b/d/e.txt"}), got)
+	})
+}
+
+func TestLsRecursive(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name      string
+		paths     []string
+		wantError bool
+		want      []string
+	}{
+		{
+			name:  "single file",
+			paths: []string{filepath.Join(dir, "a.txt")},
+			want:  []string{"a.txt"},
+		},
+		{
+			name:  "directory",
+			paths: []string{dir},
+			want:  []string{"a.txt", "b/c.txt", "b/d/e.txt"},
+		},
+		{
+			name:  "sub directory",
+			paths: []string{filepath.Join(dir, "b")},
+			want:  []string{"b/c.txt", "b/d/e.txt"},
+		},
+		{
+			name:  "glob",
+			paths: []string{filepath.Join(dir, "b*")},
+			want:  []string{"b/c.txt", "b/d/e.txt"},
+		},
+		{
+			name:      "error with successful path",
+			paths:     []string{filepath.Join(dir, "no-such-file"), filepath.Join(dir, "b", "d")},
+			wantError: true,
+			want:      []string{"b/d/e.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := LsRecursive(tt.paths...)
+			got, err := files.Slice()
+			require.Equal(t, tt.wantError, err != nil)
+			assert.Equal(t, inDir(dir, tt.want), got)
+			assert.Equal(t, len(tt.want), len(files.Files))
+		})
+	}
+}
+
+func TestLsWith_absolute(t *testing.T) {
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	want := inDir(wd, []string{"testdata/a.txt", "testdata/b.txt"})
+
+	t.Run("ls", func(t *testing.T) {
+		files := LsWith(LsOptions{Absolute: true}, "testdata")
+		got, err := files.Slice()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, want, paths(files))
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		got, err := LsRecursiveWith(LsOptions{Absolute: true}, "./testdata/../testdata").Slice()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("absolute input", func(t *testing.T) {
+		got, err := LsWith(LsOptions{Absolute: true}, filepath.Join(wd, "testdata")).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestStat(t *testing.T) {
+	t.Parallel()
+
+	file, err := Stat("testdata/b.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "testdata/b.txt", file.Path)
+	assert.Equal(t, "b.txt", file.Name())
+	assert.Equal(t, int64(3), file.Size())
+
+	_, err = Stat("no-such-file")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no-such-file")
+}
+
+func TestFileInfoAbsRel(t *testing.T) {
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	file := Ls("testdata/a.txt").Files[0]
+
+	abs, err := file.Abs()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(wd, "testdata", "a.txt"), abs)
+
+	rel, err := file.Rel("testdata")
+	require.NoError(t, err)
+	assert.Equal(t, "a.txt", rel)
+
+	rel, err = file.Rel(filepath.Join(wd, "testdata"))
+	require.NoError(t, err)
+	assert.Equal(t, "a.txt", rel)
+
+	assert.Equal(t, "testdata/a.txt", file.Path)
+}
+
+func TestFileInfoSymlinkTarget(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Symlink("a.txt", filepath.Join(dir, "link")))
+
+	files := Ls(dir).Files
+	require.Len(t, files, 3)
+	require.Equal(t, filepath.Join(dir, "link"), files[2].Path)
+
+	target, err := files[2].SymlinkTarget()
+	require.NoError(t, err)
+	assert.Equal(t, "a.txt", target)
+
+	_, err = files[0].SymlinkTarget()
+	assert.Error(t, err)
+}
+
+func TestLsRecursive_depth(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+
+	depths := func(f Files) []int {
+		var out []int
+		for _, file := range f.Files {
+			out = append(out, file.Depth)
+		}
+		return out
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, depths(LsRecursive(dir)))
+	assert.Equal(t, []int{1, 2}, depths(LsRecursive(filepath.Join(dir, "b"))))
+	assert.Equal(t, []int{0}, depths(LsRecursive(filepath.Join(dir, "a.txt"))))
+	assert.Equal(t, []int{2, 3}, depths(FindFiles(dir, "[ce].txt")))
+	assert.Equal(t, []int{0, 0}, depths(Ls(dir)))
+}
+
+func TestLsRecursiveWith(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".h"), 0775))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".h", "f.txt"), nil, 0664))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b", ".g.txt"), nil, 0664))
+
+	tests := []struct {
+		name  string
+		opts  LsOptions
+		paths []string
+		want  []string
+	}{
+		{
+			name:  "max depth 1",
+			opts:  LsOptions{IncludeHidden: true, MaxDepth: 1},
+			paths: []string{dir},
+			want:  []string{"a.txt"},
+		},
+		{
+			name:  "max depth 2",
+			opts:  LsOptions{MaxDepth: 2},
+			paths: []string{dir},
+			want:  []string{"a.txt", "b/c.txt"},
+		},
+		{
+			name:  "max depth relative to each path",
+			opts:  LsOptions{MaxDepth: 1},
+			paths: []string{dir, filepath.Join(dir, "b", "d")},
+			want:  []string{"a.txt", "b/d/e.txt"},
+		},
+		{
+			name:  "include hidden",
+			opts:  LsOptions{IncludeHidden: true},
+			paths: []string{dir},
+			want:  []string{".h/f.txt", "a.txt", "b/.g.txt", "b/c.txt", "b/d/e.txt"},
+		},
+		{
+			name:  "exclude hidden",
+			paths: []string{dir},
+			want:  []string{"a.txt", "b/c.txt", "b/d/e.txt"},
+		},
+		{
+			name:  "hidden path that was explicitly given",
+			paths: []string{filepath.Join(dir, ".h")},
+			want:  []string{".h/f.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LsRecursiveWith(tt.opts, tt.paths...).Slice()
+			require.NoError(t, err)
+			assert.Equal(t, inDir(dir, tt.want), got)
+		})
+	}
+}
+
+func TestFindFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+
+	t.Run("pattern", func(t *testing.T) {
+		got, err := FindFiles(dir, "[ce].txt").Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"b/c.txt", "b/d/e.txt"}), got)
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		got, err := FindFiles(dir, "*.go").Slice()
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := FindFiles(dir, "[").Slice()
+		assert.Error(t, err)
+	})
+
+	t.Run("no such root", func(t *testing.T) {
+		_, err := FindFiles(filepath.Join(dir, "no-such-dir"), "*").Slice()
+		assert.Error(t, err)
+	})
+}
+
+// testTree creates a temporary directory with the following tree, and returns its path:
+//
+//  a.txt
+//  b/
+//    c.txt
+//    d/
+//      e.txt
+func testTree(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	for _, path := range []string{"a.txt", "b/c.txt", "b/d/e.txt"} {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0775))
+		require.NoError(t, ioutil.WriteFile(path, []byte(path+"\n"), 0664))
+	}
+	return dir
+}
+
+// inDir joins the given paths to a given directory.
+func inDir(dir string, paths []string) []string {
+	var out []string
+	for _, path := range paths {
+		out = append(out, filepath.Join(dir, path))
+	}
+	return out
+}
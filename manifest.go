@@ -0,0 +1,46 @@
+package script
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Manifest returns a stream with a line for each file that contains the hex encoded SHA-256 digest
+// of its content, its size in bytes, its modification time in UTC and its path, separated by tabs.
+// Directories are omitted. The output of two manifests can be compared, for example using `Diff`,
+// in order to detect changed files.
+//
+// The files are hashed concurrently, with a worker for each CPU, and the content of each file is
+// hashed as it is read, without storing it. The lines are ordered according to the order of the
+// files. If a file fails to be hashed, it will result in an error in the output, and the file will
+// be omitted.
+//
+// Shell command: `sha256sum <files>`.
+func (f Files) Manifest() Stream {
+	infos := make(map[string]FileInfo, len(f.Files))
+	var paths strings.Builder
+	for _, file := range f.Files {
+		if file.IsDir() {
+			continue
+		}
+		infos[file.Path] = file
+		paths.WriteString(file.Path)
+		paths.WriteByte('\n')
+	}
+
+	return f.stream("manifest", strings.NewReader(paths.String())).Modify(&parallelLines{
+		name:    "manifest",
+		workers: runtime.NumCPU(),
+		fn: func(path string) ([]byte, error) {
+			sum, err := OpenFile(path).SHA256Sum()
+			if err != nil {
+				return nil, err
+			}
+			file := infos[path]
+			modTime := file.ModTime().UTC().Format(time.RFC3339Nano)
+			return []byte(fmt.Sprintf("%s\t%d\t%s\t%s\n", sum, file.Size(), modTime, path)), nil
+		},
+	})
+}
@@ -0,0 +1,57 @@
+package script
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	var want []string
+	for _, path := range inDir(dir, []string{"a.txt", "b/c.txt", "b/d/e.txt"}) {
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+		sum := sha256.Sum256([]byte(path + "\n"))
+		want = append(want, fmt.Sprintf("%s\t%d\t2020-01-02T03:04:05Z\t%s", hex.EncodeToString(sum[:]), len(path)+1, path))
+	}
+
+	t.Run("files", func(t *testing.T) {
+		got, err := LsRecursive(dir).Manifest().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("dirs omitted", func(t *testing.T) {
+		got, err := Ls(dir).Manifest().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, want[:1], got)
+	})
+
+	t.Run("unreadable file", func(t *testing.T) {
+		path := tempFile(t, "x\n")
+		files := LsRecursive(filepath.Join(dir, "a.txt"), path, filepath.Join(dir, "b"))
+		require.NoError(t, os.Remove(path))
+		got, err := files.Manifest().Slice()
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, os.ErrNotExist), "%v", err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("listing error", func(t *testing.T) {
+		got, err := Ls("no-such-file", filepath.Join(dir, "a.txt")).Manifest().Slice()
+		assert.Error(t, err)
+		assert.Equal(t, want[:1], got)
+	})
+}
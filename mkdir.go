@@ -0,0 +1,33 @@
+package script
+
+import (
+	"fmt"
+	"os"
+)
+
+// Mkdir creates a directory with the given permissions, and returns it as a files list. If the
+// directory already exists, it results in an error.
+//
+// Shell command: `mkdir -m <perm> <path>`.
+func Mkdir(path string, perm os.FileMode) Files {
+	return mkdir(fmt.Sprintf("mkdir(%s)", path), path, func() error { return os.Mkdir(path, perm) })
+}
+
+// MkdirAll creates a directory with the given permissions, together with any missing parents, and
+// returns it as a files list. If the directory already exists, it does nothing.
+//
+// Shell command: `mkdir -p -m <perm> <path>`.
+func MkdirAll(path string, perm os.FileMode) Files {
+	return mkdir(fmt.Sprintf("mkdir -p(%s)", path), path, func() error { return os.MkdirAll(path, perm) })
+}
+
+func mkdir(stage, path string, create func() error) Files {
+	if err := create(); err != nil {
+		return newFiles(stage, nil, pathError("create dir", path, err))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return newFiles(stage, nil, pathError("stat path", path, err))
+	}
+	return newFiles(stage, []FileInfo{{FileInfo: info, Path: path}}, nil)
+}
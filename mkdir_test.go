@@ -0,0 +1,58 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMkdir(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+
+	t.Run("mkdir", func(t *testing.T) {
+		path := filepath.Join(dir, "new")
+		files := Mkdir(path, 0755)
+		got, err := files.Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{path}, got)
+		require.Len(t, files.Files, 1)
+		assert.True(t, files.Files[0].IsDir())
+	})
+
+	t.Run("mkdir existing", func(t *testing.T) {
+		_, err := Mkdir(filepath.Join(dir, "b"), 0755).Slice()
+		assert.Error(t, err)
+	})
+
+	t.Run("mkdir missing parent", func(t *testing.T) {
+		_, err := Mkdir(filepath.Join(dir, "x", "y"), 0755).Slice()
+		assert.Error(t, err)
+	})
+
+	t.Run("mkdir all", func(t *testing.T) {
+		path := filepath.Join(dir, "p", "q")
+		got, err := MkdirAll(path, 0755).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{path}, got)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+	})
+
+	t.Run("mkdir all existing", func(t *testing.T) {
+		got, err := MkdirAll(filepath.Join(dir, "b"), 0755).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "b")}, got)
+	})
+
+	t.Run("mkdir all on file", func(t *testing.T) {
+		_, err := MkdirAll(filepath.Join(dir, "a.txt"), 0755).Slice()
+		assert.Error(t, err)
+	})
+}
@@ -4,14 +4,22 @@ import (
 	"bufio"
 	"io"
 	"reflect"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
 )
 
 // Modifier modifies input lines to output. On each line of the input the Modify method is called,
 // and the modifier can change it, omit it, or break the iteration.
+//
+// If the modifier also implements `io.Closer`, it is closed when the stream is closed, and the
+// returned error is added to the stream errors. This enables a modifier to report errors without
+// breaking the iteration.
 type Modifier interface {
 	// Modify a line. The input of this function will always be a single line from the input of the
 	// stream, without the trailing '\n'. It should return the output of the stream and should
-	// append a trailing '\n' if it want it to be a line in the output.
+	// append a trailing '\n' if it want it to be a line in the output. The modifier owns the
+	// given line, and may store it for later use.
 	//
 	// When EOF of input stream is met, the function will be called once more with a nil line value
 	// to enable output any buffered data.
@@ -36,13 +44,97 @@ func (s Stream) Modify(modifier Modifier) Stream {
 	return s.Through(modPipe{Modifier: modifier})
 }
 
+// EachLine calls the given function for each line of the input, without the trailing '\n'. The
+// function writes to out what should be written to the output for that line, including a trailing
+// '\n' if it should be a line in the output. Writing nothing omits the line.
+func (s Stream) EachLine(f func(line string, out *strings.Builder)) Stream {
+	return s.Modify(eachLine(f))
+}
+
+// Enumerate calls the given function for each line of the input, without the trailing '\n',
+// together with the zero based index of the line. The returned string is written to the output as
+// is: it should contain a trailing '\n' if it should be a line in the output, and an empty string
+// omits the line.
+func (s Stream) Enumerate(f func(i int, line string) string) Stream {
+	return s.Modify(&enumerate{f: f})
+}
+
+type enumerate struct {
+	f func(i int, line string) string
+	i int
+}
+
+func (e *enumerate) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	out := e.f(e.i, string(line))
+	e.i++
+	return []byte(out), nil
+}
+
+func (e *enumerate) Name() string {
+	return "enumerate"
+}
+
+type eachLine func(line string, out *strings.Builder)
+
+func (e eachLine) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	var out strings.Builder
+	e(string(line), &out)
+	return []byte(out.String()), nil
+}
+
+func (e eachLine) Name() string {
+	return "each-line"
+}
+
+// mapLines is a modifier that replaces each line with the result of a function.
+type mapLines struct {
+	name string
+	fn   func(line []byte) []byte
+}
+
+func (m mapLines) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	return append(m.fn(line), '\n'), nil
+}
+
+func (m mapLines) Name() string {
+	return m.name
+}
+
+// filterLines is a modifier that keeps only lines for which a function returns true.
+type filterLines struct {
+	name string
+	keep func(line []byte) bool
+}
+
+func (f filterLines) Modify(line []byte) ([]byte, error) {
+	if line == nil || !f.keep(line) {
+		return nil, nil
+	}
+	return append(line, '\n'), nil
+}
+
+func (f filterLines) Name() string {
+	return f.name
+}
+
 // modPipe takes a Modifier and exposes the Pipe interface.
 type modPipe struct {
 	Modifier
 	r *bufio.Reader
 	// partialOut stores leftover of a line that was not fully read by output.
 	partialOut []byte
-	err        error
+	// eof indicates that the input was fully read, while its last line was not modified yet.
+	eof bool
+	err error
 }
 
 func (m modPipe) Pipe(stdin io.Reader) (io.Reader, error) {
@@ -51,10 +143,16 @@ func (m modPipe) Pipe(stdin io.Reader) (io.Reader, error) {
 }
 
 func (m modPipe) Close() error {
-	if m.err == io.EOF {
-		return nil
+	var errors *multierror.Error
+	if m.err != nil && m.err != io.EOF {
+		errors = multierror.Append(errors, m.err)
+	}
+	if closer, ok := m.Modifier.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			errors = multierror.Append(errors, err)
+		}
 	}
-	return m.err
+	return errors.ErrorOrNil()
 }
 
 func (m *modPipe) Read(out []byte) (n int, err error) {
@@ -66,34 +164,47 @@ func (m *modPipe) Read(out []byte) (n int, err error) {
 		return 0, m.err
 	}
 
-	// partialIn stores a line that was not fully read from input.
-	var partialIn []byte
+	line, err := m.readLine()
+	if err != nil {
+		if err != io.EOF {
+			return 0, err
+		}
+		// Remember that we have EOF for next read call.
+		m.err = io.EOF
+	}
 
+	line, err = m.Modifier.Modify(line)
+	if err != nil {
+		m.err = err
+	}
+
+	m.partialOut, n = copyBytes(out, line)
+	return n, nil
+}
+
+// readLine returns the next line of the input, without the trailing '\n', or nil and io.EOF when
+// the input is done. The line is copied since the reader's buffer is overridden by the next read,
+// and the modifier may store it.
+func (m *modPipe) readLine() ([]byte, error) {
+	if m.eof {
+		return nil, io.EOF
+	}
+	line := []byte{}
 	for {
-		line, isPrefix, err := m.r.ReadLine()
+		chunk, isPrefix, err := m.r.ReadLine()
 		if err != nil {
-			if err != io.EOF {
-				return 0, err
+			// A last line without a trailing '\n' that is longer than the reader's buffer ends
+			// with EOF instead of a chunk that is not a prefix.
+			if err == io.EOF && len(line) > 0 {
+				m.eof = true
+				return line, nil
 			}
-			// Remember that we have EOF for next read call.
-			m.err = io.EOF
-		}
-		if len(partialIn) > 0 {
-			line = append(partialIn, line...)
-			partialIn = nil
+			return nil, err
 		}
-		if isPrefix {
-			partialIn = line
-			continue
+		line = append(line, chunk...)
+		if !isPrefix {
+			return line, nil
 		}
-
-		line, err = m.Modifier.Modify(line)
-		if err != nil {
-			m.err = err
-		}
-
-		m.partialOut, n = copyBytes(out, line)
-		return n, nil
 	}
 }
 
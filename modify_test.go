@@ -46,6 +46,12 @@ func TestModify(t *testing.T) {
 			input:    "a\nb\nc",
 			want:     "@a@\n@b@\n@c@\n",
 		},
+		{
+			name:     "empty line",
+			modifier: ModifyFn(testModifier),
+			input:    "a\n\nb",
+			want:     "@a@\n@@\n@b@\n",
+		},
 		{
 			name:     "long line correctness",
 			modifier: ModifyFn(testModifier),
@@ -69,9 +75,64 @@ func TestModify(t *testing.T) {
 	}
 }
 
+func TestModify_unterminatedLastLine(t *testing.T) {
+	t.Parallel()
+
+	// Lines of a multiple of the bufio read-line buffer size are read without a last chunk.
+	for _, size := range []int{1, 4095, 4096, 4097, 8192} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			line := strings.Repeat("x", size)
+
+			got, err := From("x", strings.NewReader(line)).Grep("x").ToString()
+			require.NoError(t, err)
+			assert.Equal(t, line+"\n", got)
+
+			got, err = From("x", strings.NewReader("a\n"+line)).Reverse().ToString()
+			require.NoError(t, err)
+			assert.Equal(t, line+"\na\n", got)
+		})
+	}
+}
+
 func TestModify_error(t *testing.T) {
 	t.Parallel()
 	got, err := Echo("a").Modify(ModifyFn(testErrorModifier)).ToString()
 	assert.Error(t, err)
 	assert.Equal(t, "", got)
 }
+
+func TestEachLine(t *testing.T) {
+	t.Parallel()
+
+	got, err := Echo("a\nb\nc").EachLine(func(line string, out *strings.Builder) {
+		if line == "b" {
+			return
+		}
+		out.WriteString(line + line + "\n")
+	}).ToString()
+	require.NoError(t, err)
+	assert.Equal(t, "aa\ncc\n", got)
+}
+
+func TestEnumerate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("index", func(t *testing.T) {
+		got, err := Echo("a\nb\nc").Enumerate(func(i int, line string) string {
+			return fmt.Sprintf("%d,%s\n", i, line)
+		}).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "0,a\n1,b\n2,c\n", got)
+	})
+
+	t.Run("every second line", func(t *testing.T) {
+		got, err := Echo("a\nb\nc\nd\ne").Enumerate(func(i int, line string) string {
+			if i%2 != 0 {
+				return ""
+			}
+			return line + "\n"
+		}).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\nc\ne\n", got)
+	})
+}
@@ -0,0 +1,71 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// MoveTo moves all the files into the given directory, which is created if it does not exist. If a
+// file can't be renamed, for example when the directory is on a different file system, it is
+// copied and then removed. It returns the files in their new locations.
+//
+// Files that have the same name, for example from a recursive listing, are not moved, since they
+// would overwrite each other, and each of them results in an error.
+//
+// If any of the files fails to be moved, it will result in an error, but the other files will
+// still be moved. The returned files contain only the files that were moved successfully, and the
+// returned error is also part of their stream errors.
+//
+// Shell command: `mv <files> <dir>`.
+func (f Files) MoveTo(dir string) (Files, error) {
+	stage := fmt.Sprintf("mv(%s)", dir)
+	if err := os.MkdirAll(dir, 0775); err != nil {
+		out := f.with(stage, nil)
+		out.err = pathError("create dir", dir, err)
+		return out, out.err
+	}
+
+	var (
+		files  []FileInfo
+		errors *multierror.Error
+	)
+	shared := collisions(dir, f.Files)
+	for _, file := range f.Files {
+		dst := destination(dir, file)
+		if shared[dst] {
+			errors = multierror.Append(errors, collisionError("move", file, dst))
+			continue
+		}
+		if err := move(file.Path, dst); err != nil {
+			errors = multierror.Append(errors, pathError("move", file.Path, err))
+			continue
+		}
+		info, err := os.Lstat(dst)
+		if err != nil {
+			errors = multierror.Append(errors, pathError("stat path", dst, err))
+			continue
+		}
+		files = append(files, FileInfo{FileInfo: info, Path: dst})
+	}
+
+	out := f.with(stage, files)
+	out.err = errors.ErrorOrNil()
+	return out, out.err
+}
+
+// move renames src to dst, and falls back to copying and removing if they are on different file
+// systems.
+func move(src, dst string) error {
+	err := os.Rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyFile(src, dst, true); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
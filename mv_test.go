@@ -0,0 +1,85 @@
+package script
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveTo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("move", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+		dst := filepath.Join(dir, "dst", "sub")
+
+		files, err := Ls(filepath.Join(dir, "a.txt"), filepath.Join(dir, "b", "c.txt")).MoveTo(dst)
+		require.NoError(t, err)
+		want := inDir(dst, []string{"a.txt", "c.txt"})
+		assert.Equal(t, want, paths(files))
+		got, err := files.Slice()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		content, err := ioutil.ReadFile(filepath.Join(dst, "a.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "a.txt")+"\n", string(content))
+		_, err = os.Stat(filepath.Join(dir, "a.txt"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("partial failure", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+		dst := filepath.Join(dir, "dst")
+
+		src := Ls(filepath.Join(dir, "a.txt"), filepath.Join(dir, "b", "c.txt"))
+		// Remove a file after it was listed, so moving it fails.
+		require.NoError(t, os.Remove(filepath.Join(dir, "a.txt")))
+
+		files, err := src.MoveTo(dst)
+		assert.Error(t, err)
+		got, err := files.Slice()
+		assert.Error(t, err)
+		assert.Equal(t, []string{filepath.Join(dst, "c.txt")}, got)
+	})
+
+	t.Run("same name", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+		dst := filepath.Join(dir, "dst")
+		same := filepath.Join(dir, "b", "d", "a.txt")
+		require.NoError(t, ioutil.WriteFile(same, []byte("same\n"), 0664))
+
+		files, err := LsRecursive(dir).RegularFiles().MoveTo(dst)
+		var errs *multierror.Error
+		require.True(t, errors.As(err, &errs))
+		assert.Len(t, errs.Errors, 2, "an error for each file with the same name")
+		assert.Equal(t, []string{filepath.Join(dst, "c.txt"), filepath.Join(dst, "e.txt")}, paths(files))
+		// The files with the same name are kept in place.
+		for _, path := range []string{filepath.Join(dir, "a.txt"), same} {
+			_, err = os.Stat(path)
+			assert.NoError(t, err)
+		}
+		_, err = os.Stat(filepath.Join(dst, "a.txt"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		files, err := Ls("no-such-file", filepath.Join(dir, "a.txt")).MoveTo(filepath.Join(dir, "dst"))
+		require.NoError(t, err)
+		got, err := files.Slice()
+		assert.Error(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "dst", "a.txt")}, got)
+	})
+}
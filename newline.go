@@ -0,0 +1,110 @@
+package script
+
+import "io"
+
+// NormalizeNewlines converts Windows (`\r\n`) and old Mac (`\r`) line endings to `\n`, such that
+// line based stages do not keep a `\r` at the end of each line. Line endings that are split between
+// reads of the previous stage are handled. The stream is assumed to be text: a `\r` byte in binary
+// data is converted as well.
+//
+// Shell command: `sed 's/\r$//; s/\r/\n/g'`.
+func (s Stream) NormalizeNewlines() Stream {
+	return s.Through(newlinePipe{name: "normalize-newlines", crlf: false})
+}
+
+// ToCRLF converts `\n` line endings to Windows (`\r\n`) line endings. Line endings that are already
+// `\r\n` are kept as is, and lone `\r` bytes are not changed. The stream is assumed to be text, like
+// in `NormalizeNewlines`.
+//
+// Shell command: `unix2dos`.
+func (s Stream) ToCRLF() Stream {
+	return s.Through(newlinePipe{name: "to-crlf", crlf: true})
+}
+
+type newlinePipe struct {
+	name string
+	crlf bool
+}
+
+func (p newlinePipe) Pipe(stdin io.Reader) (io.Reader, error) {
+	if p.crlf {
+		return &crlfReader{r: stdin}, nil
+	}
+	return &normalizeNewlinesReader{r: stdin}, nil
+}
+
+func (p newlinePipe) Name() string {
+	return p.name
+}
+
+// normalizeNewlinesReader converts line endings to `\n` in place, since the output is never longer
+// than the input.
+type normalizeNewlinesReader struct {
+	r io.Reader
+	// cr indicates that the last read byte was `\r`, such that a following `\n` should be dropped.
+	cr bool
+}
+
+func (n *normalizeNewlinesReader) Read(b []byte) (int, error) {
+	for {
+		read, err := n.r.Read(b)
+		out := 0
+		for _, c := range b[:read] {
+			if c == '\n' && n.cr {
+				n.cr = false
+				continue
+			}
+			n.cr = c == '\r'
+			if n.cr {
+				c = '\n'
+			}
+			b[out] = c
+			out++
+		}
+		// Read again if only a dropped `\n` was read.
+		if out > 0 || err != nil || read == 0 {
+			return out, err
+		}
+	}
+}
+
+// crlfReader converts `\n` line endings to `\r\n`. Since the output is longer than the input,
+// converted bytes that did not fit in the read buffer are kept for the next read.
+type crlfReader struct {
+	r io.Reader
+	// cr indicates that the last read byte was `\r`, such that a following `\n` is kept as is.
+	cr  bool
+	buf []byte
+	// out are the converted bytes, of which the first off bytes were already read.
+	out []byte
+	off int
+	// err is the error of the last read from r, returned once out was fully read.
+	err error
+}
+
+func (c *crlfReader) Read(b []byte) (int, error) {
+	if c.off == len(c.out) {
+		if c.err != nil {
+			return 0, c.err
+		}
+		if len(c.buf) < len(b) {
+			c.buf = make([]byte, len(b))
+		}
+		var read int
+		read, c.err = c.r.Read(c.buf[:len(b)])
+		c.out, c.off = c.out[:0], 0
+		for _, ch := range c.buf[:read] {
+			if ch == '\n' && !c.cr {
+				c.out = append(c.out, '\r')
+			}
+			c.out = append(c.out, ch)
+			c.cr = ch == '\r'
+		}
+		if len(c.out) == 0 {
+			return 0, c.err
+		}
+	}
+	n := copy(b, c.out[c.off:])
+	c.off += n
+	return n, nil
+}
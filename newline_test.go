@@ -0,0 +1,92 @@
+package script
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeNewlines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "crlf", in: "a\r\nb\r\n", want: "a\nb\n"},
+		{name: "cr", in: "a\rb\r", want: "a\nb\n"},
+		{name: "mixed", in: "a\r\n\r\nb\rc\n\rd", want: "a\n\nb\nc\n\nd"},
+		{name: "lf", in: "a\nb\n", want: "a\nb\n"},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := From("in", strings.NewReader(tt.in)).NormalizeNewlines().ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// Line endings split between reads.
+			got, err = From("in", iotest.OneByteReader(strings.NewReader(tt.in))).NormalizeNewlines().ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("lines", func(t *testing.T) {
+		got, err := From("in", strings.NewReader("a\r\nb\r\n")).NormalizeNewlines().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, got)
+	})
+}
+
+func TestToCRLF(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "lf", in: "a\nb\n", want: "a\r\nb\r\n"},
+		{name: "crlf", in: "a\r\nb\n", want: "a\r\nb\r\n"},
+		{name: "cr", in: "a\rb", want: "a\rb"},
+		{name: "empty lines", in: "\n\n", want: "\r\n\r\n"},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := From("in", strings.NewReader(tt.in)).ToCRLF().ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			got, err = From("in", iotest.OneByteReader(strings.NewReader(tt.in))).ToCRLF().ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// Reading with a small buffer keeps the converted bytes for the next reads.
+			s := From("in", strings.NewReader(tt.in)).ToCRLF()
+			var out []byte
+			buf := make([]byte, 1)
+			for {
+				n, err := s.Read(buf)
+				out = append(out, buf[:n]...)
+				if err != nil {
+					break
+				}
+			}
+			assert.Equal(t, tt.want, string(out))
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		got, err := Echo("a\nb").ToCRLF().NormalizeNewlines().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb\n", got)
+	})
+}
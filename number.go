@@ -0,0 +1,35 @@
+package script
+
+import (
+	"fmt"
+)
+
+// Number prefixes each line with its 1-based line number, right aligned, followed by a tab.
+//
+// Shell command: `cat -n`.
+func (s Stream) Number() Stream {
+	return s.NumberFrom(1)
+}
+
+// NumberFrom prefixes each line with its line number, similar to `Number`, where the first line is
+// numbered by start.
+func (s Stream) NumberFrom(start int) Stream {
+	return s.Modify(&number{next: start})
+}
+
+type number struct {
+	next int
+}
+
+func (n *number) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	out := append([]byte(fmt.Sprintf("%6d\t", n.next)), line...)
+	n.next++
+	return append(out, '\n'), nil
+}
+
+func (n *number) Name() string {
+	return "number"
+}
@@ -0,0 +1,30 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumber(t *testing.T) {
+	t.Parallel()
+
+	t.Run("number", func(t *testing.T) {
+		got, err := Echo("a\n\nb").Number().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "     1\ta\n     2\t\n     3\tb\n", got)
+	})
+
+	t.Run("from", func(t *testing.T) {
+		got, err := Echo("a\nb").NumberFrom(9).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "     9\ta\n    10\tb\n", got)
+	})
+
+	t.Run("grep", func(t *testing.T) {
+		got, err := Echo("a\nb\nab").Number().Grep("b").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "     2\tb\n     3\tab\n", got)
+	})
+}
@@ -0,0 +1,92 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// defaultPagers are the pagers that are used, in order, when the PAGER environment variable is not
+// set or its command is not found.
+var defaultPagers = []string{"less", "more"}
+
+// Less writes the output of the stream to a pager, for viewing it interactively. The pager is the
+// command in the PAGER environment variable, or `less` or `more` if it is not set. If stdout is not
+// a terminal, or no pager is found, the output is written to stdout, like `Stdout`.
+//
+// If the pager exits before reading the whole stream, for example when the user quits it, the rest
+// of the stream is not read and it is closed without an error. A failure to start the pager, or a
+// pager that exits with an error, results in an error.
+//
+// Shell command: `${PAGER:-less}`.
+func (s Stream) Less() error {
+	pager := findPager(os.Getenv("PAGER"))
+	if pager == nil || !isTerminal(os.Stdout) {
+		_, err := s.Stdout()
+		return err
+	}
+	return s.page(pager, os.Stdout, os.Stderr)
+}
+
+// page writes the output of the stream to the stdin of the given pager command, and closes the
+// stream once the pager exits.
+func (s Stream) page(pager []string, stdout, stderr io.Writer) error {
+	var errs *multierror.Error
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		errs = multierror.Append(errs, fmt.Errorf("start pager: %w", err))
+		if err := s.Close(); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		return errs.ErrorOrNil()
+	}
+
+	// The reader is wrapped to prevent io.Copy from calling the stream's WriteTo method.
+	_, err = io.Copy(stdin, struct{ io.Reader }{s.r})
+	stdin.Close()
+	// The pager may exit before reading all its input.
+	if err != nil && !errors.Is(err, syscall.EPIPE) && !errors.Is(err, os.ErrClosed) {
+		errs = multierror.Append(errs, fmt.Errorf("write to pager: %w", err))
+	}
+	if err := s.Close(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := cmd.Wait(); err != nil {
+		errs = multierror.Append(errs, fmt.Errorf("pager %s: %w", pager[0], err))
+	}
+	return errs.ErrorOrNil()
+}
+
+// findPager returns the command of the pager given in env if it is found, or of the first default
+// pager that is found. It returns nil if no pager is found.
+func findPager(env string) []string {
+	if args := strings.Fields(env); len(args) > 0 {
+		if _, err := exec.LookPath(args[0]); err == nil {
+			return args
+		}
+	}
+	for _, pager := range defaultPagers {
+		if _, err := exec.LookPath(pager); err == nil {
+			return []string{pager}
+		}
+	}
+	return nil
+}
+
+// isTerminal returns true if the given file is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,83 @@
+package script
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("content", func(t *testing.T) {
+		r := &closeRecorder{Reader: strings.NewReader("a\nb\n")}
+		var out bytes.Buffer
+		err := FromReader(r).page([]string{"cat"}, &out, ioutil.Discard)
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb\n", out.String())
+		assert.True(t, r.closed)
+	})
+
+	t.Run("pager quits", func(t *testing.T) {
+		r := &closeRecorder{Reader: strings.NewReader(strings.Repeat("line\n", 100000))}
+		var out bytes.Buffer
+		err := FromReader(r).page([]string{"head", "-n", "1"}, &out, ioutil.Discard)
+		require.NoError(t, err)
+		assert.Equal(t, "line\n", out.String())
+		assert.True(t, r.closed)
+	})
+
+	t.Run("pager killed", func(t *testing.T) {
+		r := &closeRecorder{Reader: strings.NewReader("a\n")}
+		err := FromReader(r).page([]string{"sh", "-c", "kill -9 $$"}, ioutil.Discard, ioutil.Discard)
+		var exitErr *exec.ExitError
+		assert.True(t, errors.As(err, &exitErr), "%v", err)
+		assert.True(t, r.closed)
+	})
+
+	t.Run("missing pager", func(t *testing.T) {
+		r := &closeRecorder{Reader: strings.NewReader("a\n")}
+		err := FromReader(r).page([]string{"no-such-pager"}, ioutil.Discard, ioutil.Discard)
+		assert.Error(t, err)
+		assert.True(t, r.closed)
+	})
+
+	t.Run("stream error", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Cat("testdata/a.txt", "missing").page([]string{"cat"}, &out, ioutil.Discard)
+		assert.Error(t, err)
+		assert.Equal(t, "a\n", out.String())
+	})
+}
+
+func TestFindPager(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"cat", "-n"}, findPager("cat -n"))
+
+	// Missing or unset pager falls back to the first default pager that exists.
+	var want []string
+	for _, pager := range defaultPagers {
+		if _, err := exec.LookPath(pager); err == nil {
+			want = []string{pager}
+			break
+		}
+	}
+	assert.Equal(t, want, findPager(""))
+	assert.Equal(t, want, findPager("no-such-pager"))
+}
+
+func TestLessNotTerminal(t *testing.T) {
+	// Stdout is not a terminal when running tests, so the output is written directly.
+	if isTerminal(os.Stdout) {
+		t.Skip("stdout is a terminal")
+	}
+	assert.NoError(t, Echo("hello").Less())
+}
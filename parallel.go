@@ -0,0 +1,95 @@
+package script
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// MapParallel replaces each line with the value that f returns for it, and runs f for up to the
+// given number of lines concurrently. The output is ordered according to the order of the input
+// lines, and only the results of lines that are waiting for previous lines to finish are stored in
+// memory. If f returns an error for a line, it will result in an error in the output, and
+// the line will be omitted. If workers is not positive, a single worker is used.
+func (s Stream) MapParallel(workers int, f func(line string) (string, error)) Stream {
+	if workers < 1 {
+		workers = 1
+	}
+	return s.Modify(&parallelLines{
+		name:    fmt.Sprintf("map-parallel(%d)", workers),
+		workers: workers,
+		fn: func(line string) ([]byte, error) {
+			out, err := f(line)
+			if err != nil {
+				return nil, err
+			}
+			return []byte(out + "\n"), nil
+		},
+	})
+}
+
+// parallelLines is a modifier that calls a function for lines concurrently, and outputs the
+// results in the order of the input lines.
+type parallelLines struct {
+	name    string
+	workers int
+	// fn returns the output for a line. The output is used also if an error is returned.
+	fn func(line string) ([]byte, error)
+	// pending holds the results of the running calls, in the order of the input lines.
+	pending []chan lineResult
+	errors  *multierror.Error
+}
+
+type lineResult struct {
+	line string
+	out  []byte
+	err  error
+}
+
+func (p *parallelLines) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		// Wait for all the running calls.
+		return p.collect(0), nil
+	}
+	// Wait for a free worker before starting the call for the current line.
+	out := p.collect(p.workers - 1)
+	result := make(chan lineResult, 1)
+	p.pending = append(p.pending, result)
+	go func(line string) {
+		out, err := p.fn(line)
+		result <- lineResult{line: line, out: out, err: err}
+	}(string(line))
+	return out, nil
+}
+
+// collect returns the output of the pending calls, in order, until at most n calls are still
+// pending. Calls that already finished are also collected, as long as they are first in order.
+func (p *parallelLines) collect(n int) []byte {
+	var out []byte
+	for len(p.pending) > 0 {
+		var r lineResult
+		if len(p.pending) > n {
+			r = <-p.pending[0]
+		} else {
+			select {
+			case r = <-p.pending[0]:
+			default:
+				return out
+			}
+		}
+		p.pending = p.pending[1:]
+		if r.err != nil {
+			p.errors = multierror.Append(p.errors, fmt.Errorf("line %q: %w", r.line, r.err))
+		}
+		out = append(out, r.out...)
+	}
+	return out
+}
+
+func (p *parallelLines) Close() error {
+	return p.errors.ErrorOrNil()
+}
+
+func (p *parallelLines) Name() string {
+	return p.name
+}
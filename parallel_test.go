@@ -0,0 +1,70 @@
+package script
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapParallel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ordered", func(t *testing.T) {
+		// Earlier lines take longer, such that they finish after later lines.
+		got, err := numbers(20).MapParallel(5, func(line string) (string, error) {
+			n, err := strconv.Atoi(line)
+			if err != nil {
+				return "", err
+			}
+			time.Sleep(time.Duration(20-n) * time.Millisecond)
+			return strconv.Itoa(n * n), nil
+		}).Slice()
+		require.NoError(t, err)
+		require.Len(t, got, 20)
+		for i, line := range got {
+			assert.Equal(t, strconv.Itoa(i*i), line)
+		}
+	})
+
+	t.Run("bounded workers", func(t *testing.T) {
+		var running, max int32
+		_, err := numbers(20).MapParallel(3, func(line string) (string, error) {
+			cur := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				prev := atomic.LoadInt32(&max)
+				if cur <= prev || atomic.CompareAndSwapInt32(&max, prev, cur) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return line, nil
+		}).ToString()
+		require.NoError(t, err)
+		assert.True(t, max <= 3, "max concurrent calls: %d", max)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		got, err := Echo("a\nbad\nb").MapParallel(2, func(line string) (string, error) {
+			if line == "bad" {
+				return "", errors.New("failed")
+			}
+			return strings.ToUpper(line), nil
+		}).ToString()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `line "bad": failed`)
+		assert.Equal(t, "A\nB\n", got)
+	})
+
+	t.Run("single worker", func(t *testing.T) {
+		got, err := Echo("a\nb").MapParallel(0, func(line string) (string, error) { return line + line, nil }).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "aa\nbb\n", got)
+	})
+}
@@ -0,0 +1,45 @@
+package script
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Peek reads the first n lines of the stream, and returns them together with a stream that outputs
+// the whole content of the original stream: the peeked lines followed by the rest of the input.
+// The returned lines do not contain the line break. If the stream has less than n lines, all its
+// lines are returned. The returned error is an error that occurred while reading the peeked lines,
+// errors of the stream stages are returned by the returned stream, as usual.
+func (s Stream) Peek(n int) ([]string, Stream, error) {
+	var (
+		lines []string
+		buf   bytes.Buffer
+		err   error
+	)
+	r := bufio.NewReader(s.r)
+	for len(lines) < n {
+		var line []byte
+		line, err = r.ReadBytes('\n')
+		buf.Write(line)
+		if len(line) > 0 {
+			lines = append(lines, string(bytes.TrimSuffix(line, []byte{'\n'})))
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	if err != nil {
+		err = fmt.Errorf("peek: %w", err)
+	}
+	return lines, Stream{
+		stage:  fmt.Sprintf("peek(%d)", n),
+		r:      io.MultiReader(&buf, r),
+		parent: &s,
+		ctx:    s.ctx,
+	}, err
+}
@@ -0,0 +1,61 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeek(t *testing.T) {
+	t.Parallel()
+
+	t.Run("peek", func(t *testing.T) {
+		lines, s, err := Echo("a\nb\nc").Peek(2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, lines)
+		got, err := s.ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb\nc\n", got)
+	})
+
+	t.Run("short input", func(t *testing.T) {
+		lines, s, err := From("short", strings.NewReader("a\nb")).Peek(5)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, lines)
+		got, err := s.ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb", got)
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		lines, s, err := Echo("a\nb").Peek(0)
+		require.NoError(t, err)
+		assert.Empty(t, lines)
+		got, err := s.ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb\n", got)
+	})
+
+	t.Run("choose separator", func(t *testing.T) {
+		lines, s, err := Echo("a;b\n1;2\n3;4").Peek(1)
+		require.NoError(t, err)
+		sep := ","
+		if strings.Contains(lines[0], ";") {
+			sep = ";"
+		}
+		got, err := s.ColumnSep(2, sep).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "b\n2\n4\n", got)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		lines, s, err := Cat("no-such-file", "testdata/b.txt").Peek(1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bb"}, lines)
+		got, err := s.ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "bb\n", got)
+	})
+}
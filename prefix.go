@@ -0,0 +1,38 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Prefix adds the given prefix to the beginning of each line. Empty lines are prefixed as well, such
+// that every output line starts with the prefix.
+//
+// Shell command: `sed 's/^/<p>/'`.
+func (s Stream) Prefix(p string) Stream {
+	return s.Modify(mapLines{
+		name: fmt.Sprintf("prefix(%q)", p),
+		fn:   func(line []byte) []byte { return append([]byte(p), line...) },
+	})
+}
+
+// Indent adds n spaces to the beginning of each line. Unlike `Prefix`, empty lines are kept empty,
+// so that the output does not contain trailing white spaces. If n is not positive, the lines are not
+// changed.
+//
+// Shell command: `sed '/./s/^/<n spaces>/'`.
+func (s Stream) Indent(n int) Stream {
+	if n < 1 {
+		return s
+	}
+	indent := []byte(strings.Repeat(" ", n))
+	return s.Modify(mapLines{
+		name: fmt.Sprintf("indent(%d)", n),
+		fn: func(line []byte) []byte {
+			if len(line) == 0 {
+				return line
+			}
+			return append(append([]byte(nil), indent...), line...)
+		},
+	})
+}
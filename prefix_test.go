@@ -0,0 +1,58 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefix(t *testing.T) {
+	t.Parallel()
+
+	t.Run("prefix", func(t *testing.T) {
+		got, err := Echo("a\n\nb").Prefix("  | ").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "  | a\n  | \n  | b\n", got)
+	})
+
+	t.Run("empty prefix", func(t *testing.T) {
+		got, err := Echo("a\nb").Prefix("").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb\n", got)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Cat("no-such-file", "testdata/a.txt").Prefix("> ").ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "> a\n", got)
+	})
+}
+
+func TestIndent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		n    int
+		want string
+	}{
+		{name: "indent", n: 2, want: "  a\n\n    b\n"},
+		{name: "zero", n: 0, want: "a\n\n  b\n"},
+		{name: "negative", n: -1, want: "a\n\n  b\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Echo("a\n\n  b").Indent(tt.n).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		got, err := Echo("a").Indent(2).Indent(2).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "    a\n", got)
+	})
+}
@@ -0,0 +1,55 @@
+package script
+
+import (
+	"fmt"
+	"io"
+)
+
+// OnProgress passes the stream through without changing it, and calls cb with the number of bytes
+// that passed so far, after about every `every` bytes. When the input is done, cb is called once
+// more with the total number of bytes. If every is not positive, cb is called after each read. The
+// callback is called synchronously while reading, so it should return quickly.
+func (s Stream) OnProgress(every int64, cb func(bytesSoFar int64)) Stream {
+	return s.Through(progress{every: every, cb: cb})
+}
+
+type progress struct {
+	every int64
+	cb    func(int64)
+}
+
+func (p progress) Pipe(stdin io.Reader) (io.Reader, error) {
+	return &progressReader{r: stdin, every: p.every, cb: p.cb}, nil
+}
+
+func (p progress) Name() string {
+	return fmt.Sprintf("progress(%d)", p.every)
+}
+
+type progressReader struct {
+	r     io.Reader
+	every int64
+	cb    func(int64)
+	// total is the number of bytes that were read so far.
+	total int64
+	// reported is the number of bytes in the last call to the callback.
+	reported int64
+	done     bool
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.total += int64(n)
+	if err == io.EOF {
+		if !p.done {
+			p.done = true
+			p.cb(p.total)
+		}
+		return n, err
+	}
+	if n > 0 && p.total-p.reported >= p.every {
+		p.reported = p.total
+		p.cb(p.total)
+	}
+	return n, err
+}
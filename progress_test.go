@@ -0,0 +1,38 @@
+package script
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnProgress(t *testing.T) {
+	t.Parallel()
+
+	t.Run("progress", func(t *testing.T) {
+		var calls []int64
+		r := iotest.OneByteReader(strings.NewReader("0123456789"))
+		got, err := From("text", r).OnProgress(4, func(n int64) { calls = append(calls, n) }).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "0123456789", got)
+		assert.Equal(t, []int64{4, 8, 10}, calls)
+	})
+
+	t.Run("every read", func(t *testing.T) {
+		var calls []int64
+		r := iotest.OneByteReader(strings.NewReader("abc"))
+		_, err := From("text", r).OnProgress(0, func(n int64) { calls = append(calls, n) }).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3, 3}, calls)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var calls []int64
+		_, err := From("empty", strings.NewReader("")).OnProgress(4, func(n int64) { calls = append(calls, n) }).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, []int64{0}, calls)
+	})
+}
@@ -0,0 +1,66 @@
+package script
+
+import (
+	"bytes"
+	"io"
+)
+
+// OnError replaces the stream with the stream that the handler returns, if the stream fails. The
+// stream is read when the returned stream is first read: its whole content is read and stored in
+// memory, and it is closed. If any error occurred in the stream, the handler is called with the
+// error, and the returned stream outputs the content of the stream it returns instead. Otherwise,
+// the stored content is output and the handler is not called.
+//
+// The errors of the stream are handled, such that they are not part of the errors of the returned
+// stream, which contains only the errors of the stream that the handler returns. Since the stream
+// is read lazily, `Error` of the returned stream does not report errors of the stream before it is
+// read.
+//
+// Usage:
+//
+//  script.Get(url).OnError(func(error) script.Stream { return script.Cat("cache.txt") }).String()
+//
+// Shell command: `<command> || <fallback>`.
+func (s Stream) OnError(handler func(err error) Stream) Stream {
+	return Stream{stage: "on-error", r: &onErrorReader{s: s, handler: handler}, ctx: s.ctx}
+}
+
+// onErrorReader reads the whole stream on the first read, and then outputs either its content or
+// the stream that the handler returns for its error.
+type onErrorReader struct {
+	s       Stream
+	handler func(err error) Stream
+	// r is the reader of the output, which is set on the first read.
+	r io.Reader
+	// fallback is the stream that the handler returned, if it was called.
+	fallback *Stream
+	closed   bool
+}
+
+func (o *onErrorReader) Read(b []byte) (int, error) {
+	if o.r == nil {
+		var buf bytes.Buffer
+		_, err := o.s.to(&buf)
+		o.closed = true
+		if err != nil {
+			fallback := o.handler(err)
+			o.fallback = &fallback
+			o.r = streamReader{s: fallback}
+		} else {
+			o.r = &buf
+		}
+	}
+	return o.r.Read(b)
+}
+
+// Close closes the stream if it was not read, or the stream that the handler returned.
+func (o *onErrorReader) Close() error {
+	if !o.closed {
+		o.closed = true
+		return o.s.Close()
+	}
+	if o.fallback != nil {
+		return o.fallback.Close()
+	}
+	return nil
+}
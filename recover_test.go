@@ -0,0 +1,81 @@
+package script
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		called := false
+		got, err := Echo("hello").OnError(func(error) Stream {
+			called = true
+			return Echo("fallback")
+		}).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "hello\n", got)
+		assert.False(t, called)
+	})
+
+	t.Run("creation error", func(t *testing.T) {
+		var handled error
+		got, err := Ls("no-such-file").OnError(func(err error) Stream {
+			handled = err
+			return Echo("fallback")
+		}).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "fallback\n", got)
+		var pathErr *PathError
+		assert.True(t, errors.As(handled, &pathErr))
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		// The content that was read before the error is not output.
+		got, err := Cat("testdata/a.txt", "no-such-file").OnError(func(error) Stream {
+			return Cat("testdata/b.txt")
+		}).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "bb\n", got)
+	})
+
+	t.Run("fallback error", func(t *testing.T) {
+		got, err := Cat("no-such-file").OnError(func(error) Stream {
+			return Cat("no-such-fallback", "testdata/b.txt")
+		}).ToString()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no-such-fallback")
+		assert.NotContains(t, err.Error(), "no-such-file:")
+		assert.Equal(t, "bb\n", got)
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		r := &closeRecorder{Reader: strings.NewReader("a\n")}
+		s := FromReader(r).OnError(func(error) Stream { return Echo("fallback") })
+		assert.Nil(t, s.Error())
+		assert.False(t, r.closed)
+		got, err := s.ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\n", got)
+		assert.True(t, r.closed)
+	})
+
+	t.Run("closed without reading", func(t *testing.T) {
+		r := &closeRecorder{Reader: strings.NewReader("a\n")}
+		require.NoError(t, FromReader(r).OnError(func(error) Stream { return Echo("fallback") }).Close())
+		assert.True(t, r.closed)
+	})
+
+	t.Run("following stages", func(t *testing.T) {
+		got, err := Fail(errors.New("boom")).OnError(func(error) Stream {
+			return Echo("a\nb\nc")
+		}).Grep("b").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "b\n", got)
+	})
+}
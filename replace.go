@@ -0,0 +1,166 @@
+package script
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Replace replaces all the occurrences of old with new in each line.
+//
+// Shell command: `sed 's/<old>/<new>/g'`.
+func (s Stream) Replace(old, new string) Stream {
+	return s.Modify(replace{old: []byte(old), new: []byte(new)})
+}
+
+// ReplaceRegexp replaces all the matches of the regexp in each line with the replacement
+// template. Inside repl, `$` signs are interpreted as in `regexp.Regexp.Expand`, such that `$1`
+// represents the text of the first submatch.
+//
+// Shell command: `sed -E 's/<re>/<repl>/g'`.
+func (s Stream) ReplaceRegexp(re *regexp.Regexp, repl string) Stream {
+	return s.Modify(replaceRegexp{re: re, repl: []byte(repl)})
+}
+
+// ReplaceFunc replaces all the matches of the regexp in each line with the result of calling f with
+// the matched text. The replacement is not expanded, such that `$` signs in it are kept. Matches do
+// not span multiple lines.
+//
+// Shell command: `perl -pe 's/<re>/f($&)/ge'`.
+func (s Stream) ReplaceFunc(re *regexp.Regexp, f func(match string) string) Stream {
+	return s.Modify(mapLines{
+		name: fmt.Sprintf("replace-func(%v)", re),
+		fn:   func(line []byte) []byte { return []byte(re.ReplaceAllStringFunc(string(line), f)) },
+	})
+}
+
+// ReplaceRegexpAll replaces all the matches of the regexp in the whole content of the stream with
+// the replacement template, such that matches may span multiple lines, as in
+// `regexp.Regexp.ReplaceAll`. Use the `(?s)` flag for `.` to match line breaks, and the `(?m)`
+// flag for `^` and `$` to match at the beginning and end of lines. Unlike `ReplaceRegexp`, that
+// replaces each line as it is read, the whole content of the stream is stored in memory until the
+// input is done.
+//
+// Shell command: `perl -0pe 's/<re>/<repl>/g'`.
+func (s Stream) ReplaceRegexpAll(re *regexp.Regexp, repl string) Stream {
+	return s.Through(replaceRegexpAll{re: re, repl: []byte(repl)})
+}
+
+// ReplaceAll replaces all the occurrences of the keys of pairs with their values in each line, in a
+// single pass. Each line is scanned from its start, and at each position the longest key that
+// matches is replaced, such that replaced text is not replaced again. Keys of the same length never
+// match at the same position, so the result does not depend on the map order. Empty keys are
+// ignored.
+//
+// Shell command: `sed 's/<key1>/<value1>/g; s/<key2>/<value2>/g...'`.
+func (s Stream) ReplaceAll(pairs map[string]string) Stream {
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	// The replacer compares keys in the order of its arguments, so give longer keys precedence.
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	oldnew := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		oldnew = append(oldnew, key, pairs[key])
+	}
+	r := strings.NewReplacer(oldnew...)
+	return s.Modify(mapLines{
+		name: fmt.Sprintf("replace-all(%d)", len(keys)),
+		fn:   func(line []byte) []byte { return []byte(r.Replace(string(line))) },
+	})
+}
+
+// ExpandEnv replaces `$VAR` and `${VAR}` in each line with the value of the environment variable.
+// Undefined variables are replaced with an empty string, as in `os.ExpandEnv`.
+//
+// Shell command: `envsubst`.
+func (s Stream) ExpandEnv() Stream {
+	return s.Expand(os.Getenv)
+}
+
+// Expand replaces `$VAR` and `${VAR}` in each line with the value that mapping returns for `VAR`,
+// as in `os.Expand`.
+func (s Stream) Expand(mapping func(string) string) Stream {
+	return s.Modify(mapLines{
+		name: "expand",
+		fn:   func(line []byte) []byte { return []byte(os.Expand(string(line), mapping)) },
+	})
+}
+
+type replace struct {
+	old, new []byte
+}
+
+func (r replace) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	return append(bytes.Replace(line, r.old, r.new, -1), '\n'), nil
+}
+
+func (r replace) Name() string {
+	return fmt.Sprintf("replace(%q, %q)", r.old, r.new)
+}
+
+type replaceRegexp struct {
+	re   *regexp.Regexp
+	repl []byte
+}
+
+func (r replaceRegexp) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	return append(r.re.ReplaceAll(line, r.repl), '\n'), nil
+}
+
+func (r replaceRegexp) Name() string {
+	return fmt.Sprintf("replace(%v, %q)", r.re, r.repl)
+}
+
+type replaceRegexpAll struct {
+	re   *regexp.Regexp
+	repl []byte
+}
+
+func (r replaceRegexpAll) Pipe(stdin io.Reader) (io.Reader, error) {
+	return &replaceAllReader{r: stdin, re: r.re, repl: r.repl}, nil
+}
+
+func (r replaceRegexpAll) Name() string {
+	return fmt.Sprintf("replace-regexp-all(%v, %q)", r.re, r.repl)
+}
+
+// replaceAllReader reads the whole underlying reader on the first read, and outputs it after the
+// replacement.
+type replaceAllReader struct {
+	r    io.Reader
+	re   *regexp.Regexp
+	repl []byte
+	// out is the replaced content, it is nil until the underlying reader was read.
+	out *bytes.Reader
+}
+
+func (r *replaceAllReader) Read(b []byte) (int, error) {
+	if r.out == nil {
+		in, err := ioutil.ReadAll(r.r)
+		r.out = bytes.NewReader(r.re.ReplaceAll(in, r.repl))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return r.out.Read(b)
+}
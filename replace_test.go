@@ -0,0 +1,151 @@
+package script
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replace", func(t *testing.T) {
+		got, err := Echo("localhost:80\nhost\nlocalhost localhost").Replace("localhost", "prod").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "prod:80\nhost\nprod prod\n", got)
+	})
+
+	t.Run("long line", func(t *testing.T) {
+		// Create line that is long enough such that it won't be read in a single read.
+		line := strings.Repeat("a", 5000) + "foo" + strings.Repeat("a", 5000)
+		got, err := Echo(line).Replace("foo", "bar").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, strings.Replace(line, "foo", "bar", 1)+"\n", got)
+	})
+
+	t.Run("regexp", func(t *testing.T) {
+		got, err := Echo("key=value\nfoo=bar").ReplaceRegexp(regexp.MustCompile(`(\w+)=(\w+)`), "$2=$1").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "value=key\nbar=foo\n", got)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Ls("no-such-file", "testdata/a.txt").Replace("a.txt", "c.txt").ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "testdata/c.txt\n", got)
+	})
+}
+
+func TestReplaceRegexpAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("multi-line", func(t *testing.T) {
+		const log = "INFO start\nERROR failed\n\tat a.go:1\n\tat b.go:2\nINFO done\n"
+		got, err := Echo(log).ReplaceRegexpAll(regexp.MustCompile(`\n\t`), " | ").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "INFO start\nERROR failed | at a.go:1 | at b.go:2\nINFO done\n\n", got)
+	})
+
+	t.Run("dot matches new line", func(t *testing.T) {
+		got, err := Echo("a <b\nc> d <e>").ReplaceRegexpAll(regexp.MustCompile(`(?s)<(.*?)>`), "[$1]").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a [b\nc] d [e]\n", got)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Cat("no-such-file", "testdata/b.txt").ReplaceRegexpAll(regexp.MustCompile(`b\n`), "c").ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "bc", got)
+	})
+}
+
+func TestReplaceAll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		pairs map[string]string
+		want  string
+	}{
+		{
+			name:  "replace all",
+			pairs: map[string]string{"alice": "user1", "bob": "user2"},
+			want:  "user1 met user2\nuser2 and user2\ncarol\n",
+		},
+		{
+			name:  "longest key wins",
+			pairs: map[string]string{"a": "1", "al": "2", "alice": "3"},
+			want:  "3 met bob\nbob 1nd bob\nc1rol\n",
+		},
+		{
+			name:  "no chained replacement",
+			pairs: map[string]string{"alice": "bob", "bob": "alice"},
+			want:  "bob met alice\nalice and alice\ncarol\n",
+		},
+		{name: "empty key", pairs: map[string]string{"": "x"}, want: "alice met bob\nbob and bob\ncarol\n"},
+		{name: "empty", pairs: nil, want: "alice met bob\nbob and bob\ncarol\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Echo("alice met bob\nbob and bob\ncarol").ReplaceAll(tt.pairs).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplaceFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("dynamic replacement", func(t *testing.T) {
+		re := regexp.MustCompile(`[0-9]+`)
+		got, err := Echo("a1 b22\nc\n333").ReplaceFunc(re, func(match string) string {
+			n, _ := strconv.Atoi(match)
+			return strconv.Itoa(n + 1)
+		}).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a2 b23\nc\n334\n", got)
+	})
+
+	t.Run("replacement is not expanded", func(t *testing.T) {
+		re := regexp.MustCompile(`(b+)`)
+		got, err := Echo("abba").ReplaceFunc(re, func(string) string { return "$1" }).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a$1a\n", got)
+	})
+
+	t.Run("per line", func(t *testing.T) {
+		var matches []string
+		re := regexp.MustCompile(`(?s)a.*b`)
+		got, err := Echo("a-b\na\nb").ReplaceFunc(re, func(match string) string {
+			matches = append(matches, match)
+			return "x"
+		}).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "x\na\nb\n", got)
+		assert.Equal(t, []string{"a-b"}, matches)
+	})
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expand", func(t *testing.T) {
+		vars := map[string]string{"A": "1", "B": "2"}
+		got, err := Echo("a=$A\nb=${B}\nc=$C").Expand(func(name string) string { return vars[name] }).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a=1\nb=2\nc=\n", got)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		got, err := Echo("home=$HOME").ExpandEnv().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "home="+os.Getenv("HOME")+"\n", got)
+	})
+}
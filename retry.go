@@ -0,0 +1,132 @@
+package script
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"time"
+)
+
+// ExecRetry executes a command, similar to `Exec`, and executes it again if it fails, up to the
+// given number of attempts, waiting the given backoff duration between attempts. The input of the
+// stream is stored in memory in order to pipe it to each attempt, as well as the output of each
+// attempt, such that only the output of the last attempt is part of the stream. If all attempts
+// fail, the stream error contains the `*ExecError` of the last attempt.
+func (s Stream) ExecRetry(attempts int, backoff time.Duration, cmd string, args ...string) Stream {
+	return s.ExecRetryWith(RetryOptions{Attempts: attempts, Backoff: backoff}, cmd, args...)
+}
+
+// RetryOptions are options for retrying a command.
+type RetryOptions struct {
+	// Attempts is the maximal number of times to execute the command. If not positive, the
+	// command is executed once.
+	Attempts int
+	// Backoff is the duration to wait before executing the command again.
+	Backoff time.Duration
+	// Exponential doubles the backoff duration after each failed attempt.
+	Exponential bool
+}
+
+// ExecRetryWith executes a command with retries, similar to `ExecRetry`, according to the given
+// options.
+func (s Stream) ExecRetryWith(opts RetryOptions, cmd string, args ...string) Stream {
+	if opts.Attempts < 1 {
+		opts.Attempts = 1
+	}
+	return s.Through(execRetry{exe: exe{cmd: cmd, args: args, ctx: s.ctx}, opts: opts})
+}
+
+type execRetry struct {
+	exe
+	opts RetryOptions
+}
+
+func (e execRetry) Pipe(stdin io.Reader) (io.Reader, error) {
+	if e.ctx == nil {
+		e.ctx = context.Background()
+	}
+	return &execRetryReader{execRetry: e, stdin: stdin}, nil
+}
+
+func (e execRetry) Name() string {
+	return fmt.Sprintf("exec-retry(%d, %v, %+v)", e.opts.Attempts, e.cmd, e.args)
+}
+
+// execRetryReader executes the command on the first read, and then outputs the stdout of the last
+// attempt.
+type execRetryReader struct {
+	execRetry
+	stdin io.Reader
+	out   *bytes.Reader
+	// err is the error of the last attempt.
+	err error
+}
+
+func (r *execRetryReader) Read(b []byte) (int, error) {
+	if r.out == nil {
+		if err := r.run(); err != nil {
+			return 0, err
+		}
+	}
+	return r.out.Read(b)
+}
+
+func (r *execRetryReader) run() error {
+	var input []byte
+	if r.stdin != nil {
+		var err error
+		input, err = ioutil.ReadAll(r.stdin)
+		if err != nil {
+			r.out = bytes.NewReader(nil)
+			return fmt.Errorf("read input: %w", err)
+		}
+	}
+
+	backoff := r.opts.Backoff
+	var stdout []byte
+	for attempt := 0; attempt < r.opts.Attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-r.ctx.Done():
+				r.out = bytes.NewReader(stdout)
+				return nil
+			case <-time.After(backoff):
+			}
+			if r.opts.Exponential {
+				backoff *= 2
+			}
+		}
+		stdout, r.err = r.attempt(input)
+		if r.err == nil {
+			break
+		}
+	}
+	r.out = bytes.NewReader(stdout)
+	return nil
+}
+
+func (r *execRetryReader) attempt(input []byte) ([]byte, error) {
+	cmd := exec.CommandContext(r.ctx, r.cmd, r.args...)
+	if r.stdin != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if r.stderr != nil {
+		cmd.Stderr = io.MultiWriter(r.stderr, &stderr)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start process: %w", err)
+	}
+	err := r.wait(cmd, &stderr)
+	return stdout.Bytes(), err
+}
+
+// Close returns the error of the last attempt.
+func (r *execRetryReader) Close() error {
+	return r.err
+}
@@ -0,0 +1,83 @@
+package script
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecRetry(t *testing.T) {
+	t.Parallel()
+
+	// flaky returns a script that fails the given number of times before it succeeds. The script
+	// prints its stdin, and the attempt number to stderr.
+	flaky := func(t *testing.T, failures int) (string, func()) {
+		dir, err := ioutil.TempDir("", "script")
+		require.NoError(t, err)
+		script := filepath.Join(dir, "flaky")
+		counter := filepath.Join(dir, "counter")
+		content := fmt.Sprintf(`#!/bin/sh
+echo x >> %[1]s
+n=$(wc -l < %[1]s)
+cat
+echo "attempt $n" >&2
+[ $n -gt %[2]d ]
+`, counter, failures)
+		require.NoError(t, ioutil.WriteFile(script, []byte(content), 0775))
+		return script, func() { os.RemoveAll(dir) }
+	}
+
+	t.Run("success after failures", func(t *testing.T) {
+		script, cleanup := flaky(t, 2)
+		defer cleanup()
+
+		got, err := Echo("hello").ExecRetry(3, time.Millisecond, script).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "hello\n", got)
+	})
+
+	t.Run("all attempts fail", func(t *testing.T) {
+		script, cleanup := flaky(t, 3)
+		defer cleanup()
+
+		got, err := Echo("hello").ExecRetry(3, time.Millisecond, script).ToString()
+		require.Error(t, err)
+		assert.Equal(t, "hello\n", got)
+
+		merr, ok := err.(*multierror.Error)
+		require.True(t, ok)
+		require.Len(t, merr.Errors, 1)
+		execErr, ok := merr.Errors[0].(*ExecError)
+		require.True(t, ok)
+		assert.Equal(t, "attempt 3\n", execErr.Stderr)
+	})
+
+	t.Run("exponential backoff", func(t *testing.T) {
+		script, cleanup := flaky(t, 2)
+		defer cleanup()
+
+		start := time.Now()
+		opts := RetryOptions{Attempts: 3, Backoff: 20 * time.Millisecond, Exponential: true}
+		_, err := Echo("hello").ExecRetryWith(opts, script).ToString()
+		require.NoError(t, err)
+		assert.True(t, time.Since(start) >= 60*time.Millisecond)
+	})
+
+	t.Run("single attempt", func(t *testing.T) {
+		_, err := Echo("hello").ExecRetry(0, time.Millisecond, "false").ToString()
+		assert.Error(t, err)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Cat("no-such-file", "testdata/a.txt").ExecRetry(2, time.Millisecond, "cat").ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "a\n", got)
+	})
+}
@@ -0,0 +1,35 @@
+package script
+
+import (
+	"bytes"
+)
+
+// Reverse returns a stream with the lines in a reversed order. All the lines are stored in memory
+// until the input is fully read.
+//
+// Shell command: `tac`.
+func (s Stream) Reverse() Stream {
+	return s.Modify(&reverse{})
+}
+
+type reverse struct {
+	lines [][]byte
+}
+
+func (r *reverse) Modify(line []byte) ([]byte, error) {
+	if line != nil {
+		r.lines = append(r.lines, line)
+		return nil, nil
+	}
+
+	var out bytes.Buffer
+	for i := len(r.lines) - 1; i >= 0; i-- {
+		out.Write(r.lines[i])
+		out.WriteByte('\n')
+	}
+	return out.Bytes(), nil
+}
+
+func (r *reverse) Name() string {
+	return "reverse"
+}
@@ -0,0 +1,38 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reverse", func(t *testing.T) {
+		got, err := Echo("a\nb\nc").Reverse().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "c\nb\na\n", got)
+	})
+
+	t.Run("long lines", func(t *testing.T) {
+		a, b := strings.Repeat("a", 5000), strings.Repeat("b", 5000)
+		got, err := Echo(a + "\n" + b).Reverse().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, b+"\n"+a+"\n", got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := From("empty", strings.NewReader("")).Reverse().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Cat("testdata/a.txt", "no-such-file", "testdata/b.txt").Reverse().ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "bb\na\n", got)
+	})
+}
@@ -0,0 +1,45 @@
+package script
+
+import (
+	"os"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Remove removes all the files. Directories are removed only if they are empty, otherwise they
+// result in an error, see `RemoveAll` for removing directories recursively.
+//
+// If any of the files fails to be removed, it will result in an error, but the other files will
+// still be removed. The returned error also contains the errors of the previous stages.
+//
+// Shell command: `rm -d <files>`.
+func (f Files) Remove() error {
+	return f.remove(os.Remove)
+}
+
+// RemoveAll removes all the files, similar to `Remove`, and removes directories recursively with
+// all their content.
+//
+// Shell command: `rm -r <files>`.
+func (f Files) RemoveAll() error {
+	return f.remove(os.RemoveAll)
+}
+
+// RemoveDryRun returns a stream of the paths that `Remove` or `RemoveAll` would remove, one per
+// line, without removing anything.
+func (f Files) RemoveDryRun() Stream {
+	return f.stream("rm-dry-run", &filesReader{files: f.Files})
+}
+
+func (f Files) remove(rm func(string) error) error {
+	var errors *multierror.Error
+	if err := f.Error(); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	for _, file := range f.Files {
+		if err := rm(file.Path); err != nil {
+			errors = multierror.Append(errors, pathError("remove", file.Path, err))
+		}
+	}
+	return errors.ErrorOrNil()
+}
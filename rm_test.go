@@ -0,0 +1,61 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	t.Run("remove", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		err := Ls(dir).RegularFiles().Remove()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"b"}), paths(Ls(dir)))
+	})
+
+	t.Run("non-empty directory", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		err := Ls(dir).Remove()
+		assert.Error(t, err)
+		assert.Equal(t, inDir(dir, []string{"b"}), paths(Ls(dir)))
+	})
+
+	t.Run("remove all", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		err := Ls(dir).RemoveAll()
+		require.NoError(t, err)
+		assert.Empty(t, Ls(dir).Files)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		err := Ls("no-such-file", filepath.Join(dir, "a.txt")).Remove()
+		assert.Error(t, err)
+		_, err = os.Stat(filepath.Join(dir, "a.txt"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		got, err := Ls(dir).RemoveDryRun().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"a.txt", "b"}), got)
+		assert.Len(t, Ls(dir).Files, 2)
+	})
+}
@@ -0,0 +1,116 @@
+package script
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Sample outputs each line with the given probability, which should be between 0 and 1.
+func (s Stream) Sample(probability float64) Stream {
+	return s.SampleSeed(probability, time.Now().UnixNano())
+}
+
+// SampleSeed outputs each line with the given probability, similar to `Sample`, using a random
+// generator with the given seed, such that the output is reproducible.
+func (s Stream) SampleSeed(probability float64, seed int64) Stream {
+	r := rand.New(rand.NewSource(seed))
+	return s.Modify(filterLines{
+		name: fmt.Sprintf("sample(%v)", probability),
+		keep: func([]byte) bool { return r.Float64() < probability },
+	})
+}
+
+// SampleN outputs a uniform random sample of n lines, in the order they appear in the input. If
+// the input has less than n lines, all of them are output. The sample is collected in a single
+// pass, using reservoir sampling, and only n lines are stored in memory.
+func (s Stream) SampleN(n int) Stream {
+	return s.SampleNSeed(n, time.Now().UnixNano())
+}
+
+// SampleNSeed outputs a uniform random sample of n lines, similar to `SampleN`, using a random
+// generator with the given seed, such that the output is reproducible.
+func (s Stream) SampleNSeed(n int, seed int64) Stream {
+	if n < 0 {
+		n = 0
+	}
+	return s.Modify(&reservoir{n: n, r: rand.New(rand.NewSource(seed))})
+}
+
+// reservoir is a modifier that samples lines using reservoir sampling.
+type reservoir struct {
+	n int
+	r *rand.Rand
+	// seen is the number of lines that were seen so far.
+	seen  int
+	lines []indexedLine
+}
+
+type indexedLine struct {
+	i    int
+	line []byte
+}
+
+func (s *reservoir) Modify(line []byte) ([]byte, error) {
+	if line != nil {
+		if len(s.lines) < s.n {
+			s.lines = append(s.lines, indexedLine{i: s.seen, line: line})
+		} else if j := s.r.Intn(s.seen + 1); j < s.n {
+			s.lines[j] = indexedLine{i: s.seen, line: line}
+		}
+		s.seen++
+		return nil, nil
+	}
+
+	sort.Slice(s.lines, func(i, j int) bool { return s.lines[i].i < s.lines[j].i })
+	var out []byte
+	for _, l := range s.lines {
+		out = append(append(out, l.line...), '\n')
+	}
+	return out, nil
+}
+
+func (s *reservoir) Name() string {
+	return fmt.Sprintf("sample-n(%d)", s.n)
+}
+
+// Shuffle outputs the lines in a random order. All the lines are stored in memory until the input
+// is fully read.
+//
+// Shell command: `shuf`.
+func (s Stream) Shuffle() Stream {
+	return s.ShuffleSeed(time.Now().UnixNano())
+}
+
+// ShuffleSeed outputs the lines in a random order, similar to `Shuffle`, using a random generator
+// with the given seed, such that the output is reproducible.
+//
+// Shell command: `shuf --random-source=<seed>`.
+func (s Stream) ShuffleSeed(seed int64) Stream {
+	return s.Modify(&shuffle{r: rand.New(rand.NewSource(seed))})
+}
+
+// shuffle is a modifier that stores all the lines and outputs them in a random order.
+type shuffle struct {
+	r     *rand.Rand
+	lines [][]byte
+}
+
+func (s *shuffle) Modify(line []byte) ([]byte, error) {
+	if line != nil {
+		s.lines = append(s.lines, line)
+		return nil, nil
+	}
+
+	s.r.Shuffle(len(s.lines), func(i, j int) { s.lines[i], s.lines[j] = s.lines[j], s.lines[i] })
+	var out []byte
+	for _, line := range s.lines {
+		out = append(append(out, line...), '\n')
+	}
+	return out, nil
+}
+
+func (s *shuffle) Name() string {
+	return "shuffle"
+}
@@ -0,0 +1,131 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// numbers returns a stream with the numbers from 0 to n-1, one in each line.
+func numbers(n int) Stream {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintln(&b, i)
+	}
+	return From("numbers", strings.NewReader(b.String()))
+}
+
+func TestSample(t *testing.T) {
+	t.Parallel()
+
+	t.Run("probability", func(t *testing.T) {
+		got, err := numbers(10000).SampleSeed(0.1, 1).CountLines()
+		require.NoError(t, err)
+		assert.InDelta(t, 1000, got, 150)
+	})
+
+	t.Run("probability bounds", func(t *testing.T) {
+		got, err := numbers(100).Sample(0).CountLines()
+		require.NoError(t, err)
+		assert.Equal(t, 0, got)
+
+		got, err = numbers(100).Sample(1).CountLines()
+		require.NoError(t, err)
+		assert.Equal(t, 100, got)
+	})
+
+	t.Run("reproducible", func(t *testing.T) {
+		a, err := numbers(100).SampleSeed(0.5, 42).ToString()
+		require.NoError(t, err)
+		b, err := numbers(100).SampleSeed(0.5, 42).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+	})
+}
+
+func TestSampleN(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sample", func(t *testing.T) {
+		got, err := numbers(1000).SampleN(10).Slice()
+		require.NoError(t, err)
+		require.Len(t, got, 10)
+		// Lines are ordered as in the input.
+		prev := -1
+		for _, line := range got {
+			var n int
+			_, err := fmt.Sscan(line, &n)
+			require.NoError(t, err)
+			assert.True(t, n > prev)
+			prev = n
+		}
+	})
+
+	t.Run("short input", func(t *testing.T) {
+		got, err := numbers(3).SampleN(10).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "0\n1\n2\n", got)
+	})
+
+	t.Run("reproducible", func(t *testing.T) {
+		a, err := numbers(100).SampleNSeed(5, 42).ToString()
+		require.NoError(t, err)
+		b, err := numbers(100).SampleNSeed(5, 42).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+	})
+
+	t.Run("uniform", func(t *testing.T) {
+		// Each of the 4 lines should be sampled about half of the times.
+		counts := make(map[string]int)
+		for seed := int64(0); seed < 1000; seed++ {
+			got, err := numbers(4).SampleNSeed(2, seed).Slice()
+			require.NoError(t, err)
+			for _, line := range got {
+				counts[line]++
+			}
+		}
+		for _, line := range []string{"0", "1", "2", "3"} {
+			assert.InDelta(t, 500, counts[line], 80, "line %s", line)
+		}
+	})
+}
+
+func TestShuffle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("shuffle", func(t *testing.T) {
+		got, err := numbers(100).Shuffle().Slice()
+		require.NoError(t, err)
+		want, err := numbers(100).Slice()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, want, got)
+	})
+
+	t.Run("reproducible", func(t *testing.T) {
+		a, err := numbers(100).ShuffleSeed(42).ToString()
+		require.NoError(t, err)
+		b, err := numbers(100).ShuffleSeed(42).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+		c, err := numbers(100).ShuffleSeed(43).ToString()
+		require.NoError(t, err)
+		assert.NotEqual(t, a, c)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		got, err := Ls("testdata").ShuffleSeed(1).First(1).Slice()
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Contains(t, []string{"testdata/a.txt", "testdata/b.txt"}, got[0])
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := From("empty", strings.NewReader("")).Shuffle().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+}
@@ -0,0 +1,81 @@
+package script
+
+import "path/filepath"
+
+// Session creates streams that resolve relative paths against a working directory, without changing
+// the working directory of the process. Sessions with different directories can be used
+// concurrently.
+type Session struct {
+	dir string
+}
+
+// WithWorkingDir returns a session that resolves relative paths against the given directory. The
+// paths in the created streams are the resolved paths.
+//
+// Usage:
+//
+//  s := script.WithWorkingDir(dir)
+//  n, err := s.LsRecursive("src").Grep(".go").CountLines()
+//
+// Shell command: `cd <dir>`.
+func WithWorkingDir(dir string) *Session {
+	return &Session{dir: dir}
+}
+
+// Ls lists the given paths, similar to `Ls`. If no paths are given, the working directory is
+// listed.
+func (s *Session) Ls(paths ...string) Files {
+	return Ls(s.resolve(paths)...)
+}
+
+// LsWith lists the given paths according to the given options, similar to `LsWith`.
+func (s *Session) LsWith(opts LsOptions, paths ...string) Files {
+	return LsWith(opts, s.resolve(paths)...)
+}
+
+// LsRecursive lists the files under the given paths, similar to `LsRecursive`. If no paths are
+// given, the working directory is walked.
+func (s *Session) LsRecursive(paths ...string) Files {
+	return LsRecursive(s.resolve(paths)...)
+}
+
+// LsRecursiveWith lists the files under the given paths according to the given options, similar
+// to `LsRecursiveWith`.
+func (s *Session) LsRecursiveWith(opts LsOptions, paths ...string) Files {
+	return LsRecursiveWith(opts, s.resolve(paths)...)
+}
+
+// Cat outputs the contents of the given files, similar to `Cat`. If no paths are given, the stream
+// reads from stdin.
+func (s *Session) Cat(paths ...string) Stream {
+	if len(paths) == 0 {
+		return Cat()
+	}
+	return Cat(s.resolve(paths)...)
+}
+
+// OpenFile outputs the content of a single file, similar to `OpenFile`.
+func (s *Session) OpenFile(path string) Stream {
+	return OpenFile(s.path(path))
+}
+
+// resolve returns the given paths resolved against the working directory. If no paths are given,
+// it returns the working directory.
+func (s *Session) resolve(paths []string) []string {
+	if len(paths) == 0 {
+		return []string{s.dir}
+	}
+	resolved := make([]string, len(paths))
+	for i, path := range paths {
+		resolved[i] = s.path(path)
+	}
+	return resolved
+}
+
+// path returns the given path resolved against the working directory.
+func (s *Session) path(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(s.dir, path)
+}
@@ -0,0 +1,93 @@
+package script
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithWorkingDir(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	s := WithWorkingDir(dir)
+
+	t.Run("ls", func(t *testing.T) {
+		got := s.Ls("a.txt", "b")
+		require.NoError(t, got.Error())
+		assert.Equal(t, inDir(dir, []string{"a.txt", "b/c.txt", "b/d"}), paths(got))
+	})
+
+	t.Run("ls working dir", func(t *testing.T) {
+		got := s.Ls()
+		require.NoError(t, got.Error())
+		assert.Equal(t, inDir(dir, []string{"a.txt", "b"}), paths(got))
+	})
+
+	t.Run("ls glob", func(t *testing.T) {
+		got := s.Ls("b/*.txt")
+		require.NoError(t, got.Error())
+		assert.Equal(t, inDir(dir, []string{"b/c.txt"}), paths(got))
+	})
+
+	t.Run("ls recursive", func(t *testing.T) {
+		got := s.LsRecursive()
+		require.NoError(t, got.Error())
+		assert.Equal(t, inDir(dir, []string{"a.txt", "b/c.txt", "b/d/e.txt"}), paths(got))
+	})
+
+	t.Run("ls with", func(t *testing.T) {
+		got := s.LsRecursiveWith(LsOptions{MaxDepth: 1}, "b")
+		require.NoError(t, got.Error())
+		assert.Equal(t, inDir(dir, []string{"b/c.txt"}), paths(got))
+	})
+
+	t.Run("cat", func(t *testing.T) {
+		got, err := s.Cat("a.txt", "b/c.txt").String()
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "a.txt")+"\n"+filepath.Join(dir, "b/c.txt")+"\n", got)
+	})
+
+	t.Run("open file", func(t *testing.T) {
+		got, err := s.OpenFile("b/d/e.txt").String()
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "b/d/e.txt")+"\n", got)
+	})
+
+	t.Run("absolute path", func(t *testing.T) {
+		abs, err := filepath.Abs("testdata/a.txt")
+		require.NoError(t, err)
+		got, err := s.Cat(abs).String()
+		require.NoError(t, err)
+		assert.Equal(t, "a\n", got)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		err := s.Ls("testdata").Error()
+		var pathErr *PathError
+		require.True(t, errors.As(err, &pathErr))
+		assert.Equal(t, filepath.Join(dir, "testdata"), pathErr.Path)
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		dirs := []string{filepath.Join(dir, "b"), filepath.Join(dir, "b/d")}
+		got := make([][]string, len(dirs))
+		var wg sync.WaitGroup
+		for i, d := range dirs {
+			wg.Add(1)
+			go func(i int, d string) {
+				defer wg.Done()
+				got[i] = paths(WithWorkingDir(d).LsRecursive("."))
+			}(i, d)
+		}
+		wg.Wait()
+		assert.Equal(t, inDir(dir, []string{"b/c.txt", "b/d/e.txt"}), got[0])
+		assert.Equal(t, inDir(dir, []string{"b/d/e.txt"}), got[1])
+	})
+}
@@ -1,43 +1,124 @@
 package script
 
 import (
-	"bufio"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
-
-	"github.com/hashicorp/go-multierror"
+	"unicode"
 )
 
 // Sort returns a stream with lines ordered alphabetically.
 //
-// Shell command: `wc`.
+// Shell command: `sort [-r]`.
 func (s Stream) Sort(reverse bool) Stream {
-	var (
-		lines  []string
-		errors *multierror.Error
-	)
-	scanner := bufio.NewScanner(s.r)
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+	less := func(a, b string) bool { return a < b }
+	if reverse {
+		less = func(a, b string) bool { return a > b }
 	}
-	if err := scanner.Err(); err != nil {
-		errors = multierror.Append(errors, fmt.Errorf("scanning stream: %s", err))
+	return s.Modify(&sortLines{name: fmt.Sprintf("sort(%v)", reverse), less: less})
+}
+
+// SortLines returns a stream with lines ordered alphabetically. All the lines are stored in memory
+// until the input is fully read.
+//
+// Shell command: `sort`.
+func (s Stream) SortLines() Stream {
+	return s.SortLinesFunc(func(a, b string) bool { return a < b })
+}
+
+// SortLinesFunc returns a stream with lines ordered according to the given less function. Lines
+// that are equal according to the less function keep their original order. All the lines are
+// stored in memory until the input is fully read.
+func (s Stream) SortLinesFunc(less func(a, b string) bool) Stream {
+	return s.Modify(&sortLines{name: "sort-func", less: less})
+}
+
+// SortNumeric returns a stream with lines ordered by the number at the beginning of each line.
+// Leading white spaces are ignored, and lines that do not start with a number are considered as
+// zero. Lines with an equal number keep their original order. All the lines are stored in memory
+// until the input is fully read.
+//
+// Shell command: `sort -n`.
+func (s Stream) SortNumeric() Stream {
+	return s.Modify(&sortLines{
+		name: "sort-numeric",
+		less: func(a, b string) bool { return leadingNumber(a) < leadingNumber(b) },
+	})
+}
+
+// SortByColumnNumeric returns a stream with lines ordered by the number in the given white space
+// separated column. The columns are 1 based (first column is 1). Lines that do not have the column,
+// or that the column does not start with a number, are considered as zero. Lines with an equal
+// number keep their original order. All the lines are stored in memory until the input is fully
+// read.
+//
+// Shell command: `sort -n -k<col>,<col>`.
+func (s Stream) SortByColumnNumeric(col int) Stream {
+	return s.Modify(&sortLines{
+		name: fmt.Sprintf("sort-column-numeric(%d)", col),
+		less: func(a, b string) bool { return columnNumber(a, col) < columnNumber(b, col) },
+	})
+}
+
+// sortLines is a modifier that stores all the lines and outputs them sorted.
+type sortLines struct {
+	name  string
+	less  func(a, b string) bool
+	lines []string
+}
+
+func (s *sortLines) Modify(line []byte) ([]byte, error) {
+	if line != nil {
+		s.lines = append(s.lines, string(line))
+		return nil, nil
 	}
 
-	sort.Slice(lines, func(i, j int) bool { return (lines[i] < lines[j]) != reverse })
+	sort.SliceStable(s.lines, func(i, j int) bool { return s.less(s.lines[i], s.lines[j]) })
 
 	var out strings.Builder
-	for _, line := range lines {
-		_, err := out.WriteString(line + "\n")
-		if err != nil {
-			errors = multierror.Append(errors, fmt.Errorf("writing line %q: %s", line, err))
+	for _, line := range s.lines {
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return []byte(out.String()), nil
+}
+
+func (s *sortLines) Name() string {
+	return s.name
+}
+
+// leadingNumber parses the number at the beginning of the given string, ignoring leading white
+// spaces. If the string does not start with a number, it returns zero.
+func leadingNumber(s string) float64 {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	end := 0
+	if end < len(s) && (s[end] == '-' || s[end] == '+') {
+		end++
+	}
+	dot := false
+	for ; end < len(s); end++ {
+		if s[end] == '.' && !dot {
+			dot = true
+			continue
 		}
+		if s[end] < '0' || s[end] > '9' {
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return 0
 	}
+	return n
+}
 
-	return Stream{
-		stage: fmt.Sprintf("sort(%v)", reverse),
-		r:     strings.NewReader(out.String()),
-		err:   errors.ErrorOrNil(),
+// columnNumber parses the number at the beginning of the given 1 based white space separated
+// column. If the column does not exist or does not start with a number, it returns zero.
+func columnNumber(s string, col int) float64 {
+	fields := strings.Fields(s)
+	if col < 1 || col > len(fields) {
+		return 0
 	}
+	return leadingNumber(fields[col-1])
 }
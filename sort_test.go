@@ -1,6 +1,7 @@
 package script
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -22,3 +23,47 @@ func TestSort(t *testing.T) {
 		assert.Equal(t, "b\nab\na\n", out)
 	})
 }
+
+func TestSortLines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    Stream
+		want string
+	}{
+		{name: "lines", s: Echo("ab\na\nb").SortLines(), want: "a\nab\nb\n"},
+		{name: "empty", s: From("empty", strings.NewReader("")).SortLines(), want: ""},
+		{
+			name: "func",
+			s:    Echo("ccc\na\nbb\nd").SortLinesFunc(func(a, b string) bool { return len(a) < len(b) }),
+			want: "a\nd\nbb\nccc\n",
+		},
+		{name: "numeric", s: Echo("10 a\n9 b\n -1 c\nd\n1.5 e").SortNumeric(), want: " -1 c\nd\n1.5 e\n9 b\n10 a\n"},
+		{name: "numeric stable", s: Echo("1 b\n0 a\n1 a\nc").SortNumeric(), want: "0 a\nc\n1 b\n1 a\n"},
+		{
+			name: "column numeric",
+			s:    Echo("a 10\nb 9\nc\nd -1\ne x\nf 9").SortByColumnNumeric(2),
+			want: "d -1\nc\ne x\nb 9\nf 9\na 10\n",
+		},
+		{
+			name: "column numeric du",
+			s:    Echo("100\tb\n20\ta\n3\tc\n123\ttotal").SortByColumnNumeric(1),
+			want: "3\tc\n20\ta\n100\tb\n123\ttotal\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Ls("no-such-file", "testdata").Sort(true).ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "testdata/b.txt\ntestdata/a.txt\n", got)
+	})
+}
@@ -0,0 +1,142 @@
+package script
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// SplitFiles writes the stream into files of at most linesPerFile lines each, named by the prefix
+// followed by a 3 digits index: `<prefix>000`, `<prefix>001` and so on. The directory of the files
+// is created if it does not exist. The last file may contain less lines, and no file is created
+// for an empty stream. It returns the written files together with the errors that occurred in the
+// stream or while writing the files. If linesPerFile is not positive, each file contains a single
+// line.
+//
+// Shell command: `split -d -a 3 -l <linesPerFile> - <prefix>`.
+func (s Stream) SplitFiles(linesPerFile int, prefix string) (Files, error) {
+	if linesPerFile < 1 {
+		linesPerFile = 1
+	}
+	return s.split(&splitWriter{prefix: prefix, limit: int64(linesPerFile), lines: true})
+}
+
+// SplitFilesBytes writes the stream into files of at most bytesPerFile bytes each, similar to
+// `SplitFiles`. Lines may be split between files. If bytesPerFile is not positive, each file
+// contains a single byte.
+//
+// Shell command: `split -d -a 3 -b <bytesPerFile> - <prefix>`.
+func (s Stream) SplitFilesBytes(bytesPerFile int64, prefix string) (Files, error) {
+	if bytesPerFile < 1 {
+		bytesPerFile = 1
+	}
+	return s.split(&splitWriter{prefix: prefix, limit: bytesPerFile})
+}
+
+func (s Stream) split(w *splitWriter) (Files, error) {
+	var errors *multierror.Error
+	if _, err := s.to(w); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	if err := w.Close(); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	files := make([]FileInfo, 0, len(w.paths))
+	for _, path := range w.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			errors = multierror.Append(errors, pathError("stat path", path, err))
+			continue
+		}
+		files = append(files, FileInfo{FileInfo: info, Path: path})
+	}
+	err := errors.ErrorOrNil()
+	return newFiles(fmt.Sprintf("split(%s)", w.prefix), files, err), err
+}
+
+// splitWriter writes to a sequence of files, and moves to the next file after limit lines or
+// bytes were written to the current file. Files are created only when there is data to write.
+type splitWriter struct {
+	prefix string
+	limit  int64
+	// lines indicates if the limit is of lines or of bytes.
+	lines bool
+	// cur is the current file, and count is the number of lines or bytes that were written to it.
+	cur   *os.File
+	count int64
+	paths []string
+}
+
+func (w *splitWriter) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		if w.cur == nil {
+			if err := w.next(); err != nil {
+				return written, err
+			}
+		}
+
+		// Find how much can be written to the current file.
+		n := len(b)
+		if w.lines {
+			for i, c := range b {
+				if c == '\n' {
+					w.count++
+					if w.count == w.limit {
+						n = i + 1
+						break
+					}
+				}
+			}
+		} else {
+			if remaining := w.limit - w.count; int64(n) > remaining {
+				n = int(remaining)
+			}
+			w.count += int64(n)
+		}
+
+		m, err := w.cur.Write(b[:n])
+		written += m
+		if err != nil {
+			return written, pathError("write file", w.cur.Name(), err)
+		}
+		b = b[n:]
+
+		if w.count == w.limit {
+			if err := w.Close(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// next creates the next file.
+func (w *splitWriter) next() error {
+	path := fmt.Sprintf("%s%03d", w.prefix, len(w.paths))
+	if err := makeDir(path); err != nil {
+		return pathError("create dir", path, err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return pathError("create file", path, err)
+	}
+	w.cur, w.count = f, 0
+	w.paths = append(w.paths, path)
+	return nil
+}
+
+// Close closes the current file.
+func (w *splitWriter) Close() error {
+	if w.cur == nil {
+		return nil
+	}
+	err := w.cur.Close()
+	if err != nil {
+		err = pathError("close file", w.cur.Name(), err)
+	}
+	w.cur = nil
+	return err
+}
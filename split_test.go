@@ -0,0 +1,71 @@
+package script
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitFiles(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// contents returns the contents of the given files.
+	contents := func(files Files) []string {
+		var out []string
+		for _, file := range files.Files {
+			b, err := ioutil.ReadFile(file.Path)
+			require.NoError(t, err)
+			out = append(out, string(b))
+		}
+		return out
+	}
+
+	t.Run("lines", func(t *testing.T) {
+		prefix := filepath.Join(dir, "lines", "x")
+		files, err := Echo("1\n2\n3\n4\n5").SplitFiles(2, prefix)
+		require.NoError(t, err)
+		assert.Equal(t, []string{prefix + "000", prefix + "001", prefix + "002"}, paths(files))
+		assert.Equal(t, []string{"1\n2\n", "3\n4\n", "5\n"}, contents(files))
+		got, err := files.Slice()
+		require.NoError(t, err)
+		assert.Equal(t, paths(files), got)
+	})
+
+	t.Run("exact", func(t *testing.T) {
+		prefix := filepath.Join(dir, "exact", "x")
+		files, err := Echo("1\n2\n3").SplitFiles(3, prefix)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1\n2\n3\n"}, contents(files))
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		prefix := filepath.Join(dir, "bytes", "x")
+		files, err := Echo("hello world").SplitFilesBytes(5, prefix)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"hello", " worl", "d\n"}, contents(files))
+		assert.Equal(t, int64(12), files.TotalSize())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		prefix := filepath.Join(dir, "empty", "x")
+		files, err := Echo("").DropBlank().SplitFiles(2, prefix)
+		require.NoError(t, err)
+		assert.Empty(t, files.Files)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		prefix := filepath.Join(dir, "error", "x")
+		files, err := Cat("no-such-file", "testdata/b.txt").SplitFiles(1, prefix)
+		assert.Error(t, err)
+		assert.Equal(t, []string{"bb\n"}, contents(files))
+		assert.Error(t, files.Error())
+	})
+}
@@ -15,8 +15,10 @@
 package script
 
 import (
+	"context"
 	"io"
 	"reflect"
+	"strings"
 
 	"github.com/hashicorp/go-multierror"
 )
@@ -40,6 +42,8 @@ type Stream struct {
 	parent *Stream
 	// err contains an error from the current stage in the stream.
 	err error
+	// ctx is the context of the stream, if one was set using the `WithContext` method.
+	ctx context.Context
 }
 
 // Read can be used to read from the stream.
@@ -64,11 +68,58 @@ func (s Stream) Close() error {
 	return errors.ErrorOrNil()
 }
 
+// closeReaders closes the readers of all the stages in the stream, and returns the errors of closing
+// them, without the errors of the stages.
+func (s Stream) closeReaders() error {
+	var errors *multierror.Error
+	for cur := &s; cur != nil; cur = cur.parent {
+		if closer, ok := cur.r.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errors = multierror.Append(errors, err)
+			}
+		}
+	}
+	return errors.ErrorOrNil()
+}
+
+// Error returns the errors that occurred so far in all the stages of the stream, without reading
+// or closing it. It returns nil if no errors occurred. Errors that occur while the stream is being
+// read, for example when a file in `Cat` fails to be opened, are only returned by the final
+// terminal method, such as `To` or `Close`.
+func (s Stream) Error() error {
+	var errors *multierror.Error
+	for cur := &s; cur != nil; cur = cur.parent {
+		if cur.err != nil {
+			errors = multierror.Append(errors, cur.err)
+		}
+	}
+	return errors.ErrorOrNil()
+}
+
+// Errors returns all the errors that occurred so far in all the stages of the stream, without
+// reading or closing it, like the `Error` method. Errors that contain several errors are flattened,
+// such that each failure is a separate item.
+//
+// The error returned by `Error`, `Close` and the terminal methods is a `*multierror.Error`, which
+// supports `errors.Is` and `errors.As` on any of the errors it contains. The errors wrap their
+// underlying cause, such that `errors.Is(Ls("missing").Error(), os.ErrNotExist)` is true.
+func (s Stream) Errors() []error {
+	err := s.Error()
+	if err == nil {
+		return nil
+	}
+	return err.(*multierror.Error).Errors
+}
+
 // Through passes the current stream through a pipe. This function can be used to add custom
-// commands that are not available in this library.
+// commands that are not available in this library. If the pipe fails without returning a reader,
+// the returned stream is empty and contains the error.
 func (s Stream) Through(pipe Pipe) Stream {
 	r, err := pipe.Pipe(s.r)
 	if r == nil {
+		if err != nil {
+			return s.failed(pipe.Name(), err)
+		}
 		panic("a command must contain a reader")
 	}
 	return Stream{
@@ -76,6 +127,44 @@ func (s Stream) Through(pipe Pipe) Stream {
 		r:      r,
 		err:    err,
 		parent: &s,
+		ctx:    s.ctx,
+	}
+}
+
+// Apply passes the current stream through a reader that is returned by the given function. It is
+// the lowest level extension point of the stream, and can be used to plug in any reader that
+// transforms another reader, such as a decompressor. The errors of the previous stages are kept.
+// If the returned reader also implements `io.Closer`, it will be closed when the stream is closed.
+//
+// The returned reader must return `io.EOF` once the given reader is exhausted, otherwise the
+// stream never ends.
+func (s Stream) Apply(f func(r io.Reader) io.Reader) Stream {
+	return s.ApplyErr(func(r io.Reader) (io.Reader, error) { return f(r), nil })
+}
+
+// ApplyErr passes the current stream through a reader that is returned by the given function,
+// similar to `Apply`, for functions that may fail to create the reader, for example when they open
+// a file. The returned error is part of the stream errors. If the function returns an error without
+// a reader, the returned stream is empty.
+func (s Stream) ApplyErr(f func(r io.Reader) (io.Reader, error)) Stream {
+	return s.Through(applyPipe(f))
+}
+
+type applyPipe func(io.Reader) (io.Reader, error)
+
+func (a applyPipe) Pipe(stdin io.Reader) (io.Reader, error) { return a(stdin) }
+
+func (a applyPipe) Name() string { return "apply" }
+
+// failed returns an empty stream that follows the current stream with a stage that failed with the
+// given error.
+func (s Stream) failed(stage string, err error) Stream {
+	return Stream{
+		stage:  stage,
+		r:      strings.NewReader(""),
+		err:    err,
+		parent: &s,
+		ctx:    s.ctx,
 	}
 }
 
@@ -95,6 +184,19 @@ func (f PipeFn) Pipe(stdin io.Reader) (io.Reader, error) { return f(stdin) }
 
 func (f PipeFn) Name() string { return reflect.TypeOf(f).Name() }
 
+// streamReader reads a stream that is used as the reader of a stage in another stream, and closes
+// it when that stream is closed. It does not implement `io.WriterTo`, such that copying from it,
+// for example to the stdin of a command, does not close the stream as `Stream.WriteTo` does.
+type streamReader struct {
+	s Stream
+}
+
+func (r streamReader) Read(b []byte) (int, error) { return r.s.Read(b) }
+
+func (r streamReader) Close() error { return r.s.Close() }
+
+func (r streamReader) setContext(ctx context.Context) { r.s.setContext(ctx) }
+
 type readcloser struct {
 	io.Reader
 	io.Closer
@@ -2,9 +2,15 @@ package script
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // A simple "hello world" example that creats a stream and pipe it to the stdout.
@@ -100,3 +106,101 @@ func (s sum) Read(b []byte) (int, error) {
 	}
 	return 0, nil
 }
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no error", func(t *testing.T) {
+		assert.NoError(t, Ls("testdata").Grep("a").Error())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		assert.Error(t, Ls("no-such-file").Error())
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		assert.Error(t, Ls("no-such-file").Grep("a").Error())
+	})
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no error", func(t *testing.T) {
+		assert.Nil(t, Ls("testdata").Grep("a").Errors())
+	})
+
+	t.Run("multiple errors", func(t *testing.T) {
+		errs := Ls("no-such-file", "testdata", "other-file").Grep("a").Errors()
+		require.Len(t, errs, 2)
+		assert.Contains(t, errs[0].Error(), "no-such-file")
+		assert.Contains(t, errs[1].Error(), "other-file")
+	})
+
+	t.Run("errors.As", func(t *testing.T) {
+		_, err := Cat("no-such-file").Exec("false").ToString()
+		var execErr *ExecError
+		require.True(t, errors.As(err, &execErr))
+		assert.Equal(t, 1, execErr.ExitCode)
+	})
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	t.Run("apply", func(t *testing.T) {
+		got, err := Echo("hello world").Apply(func(r io.Reader) io.Reader {
+			return io.LimitReader(r, 5)
+		}).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "hello", got)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Cat("no-such-file", "testdata/a.txt").Apply(func(r io.Reader) io.Reader {
+			return r
+		}).ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "a\n", got)
+	})
+}
+
+func TestApplyErr(t *testing.T) {
+	t.Parallel()
+
+	t.Run("apply", func(t *testing.T) {
+		got, err := Echo("hello world").ApplyErr(func(r io.Reader) (io.Reader, error) {
+			return io.LimitReader(r, 5), nil
+		}).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "hello", got)
+	})
+
+	t.Run("setup error without reader", func(t *testing.T) {
+		s := Echo("hello world").ApplyErr(func(r io.Reader) (io.Reader, error) {
+			_, err := os.Open("no-such-file")
+			return nil, err
+		})
+		assert.True(t, errors.Is(s.Error(), os.ErrNotExist))
+		got, err := s.ToString()
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+		assert.Equal(t, "", got)
+	})
+
+	t.Run("setup error with reader", func(t *testing.T) {
+		boom := errors.New("boom")
+		got, err := Echo("hello").ApplyErr(func(r io.Reader) (io.Reader, error) {
+			return r, boom
+		}).ToString()
+		assert.True(t, errors.Is(err, boom))
+		assert.Equal(t, "hello\n", got)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Cat("no-such-file", "testdata/a.txt").ApplyErr(func(r io.Reader) (io.Reader, error) {
+			return r, nil
+		}).ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "a\n", got)
+	})
+}
@@ -0,0 +1,88 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExpandTabs replaces each tab with the number of spaces that reach the next tab stop, where tab
+// stops are every width columns. The columns are counted in runes from the beginning of each line.
+// If width is not positive, the lines are not changed.
+//
+// Shell command: `expand -t <width>`.
+func (s Stream) ExpandTabs(width int) Stream {
+	if width < 1 {
+		return s
+	}
+	return s.Modify(mapLines{
+		name: fmt.Sprintf("expand-tabs(%d)", width),
+		fn:   func(line []byte) []byte { return []byte(expandTabs(string(line), width)) },
+	})
+}
+
+// UnexpandTabs replaces runs of at least two spaces that end at a tab stop with a tab, where tab
+// stops are every width columns, such that it reverses `ExpandTabs`. Spaces anywhere in the line
+// are converted, and a single space before a tab stop is kept. If width is not positive, the lines
+// are not changed.
+//
+// Shell command: `unexpand -a -t <width>`.
+func (s Stream) UnexpandTabs(width int) Stream {
+	if width < 1 {
+		return s
+	}
+	return s.Modify(mapLines{
+		name: fmt.Sprintf("unexpand-tabs(%d)", width),
+		fn:   func(line []byte) []byte { return []byte(unexpandTabs(string(line), width)) },
+	})
+}
+
+func expandTabs(line string, width int) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var out strings.Builder
+	col := 0
+	for _, r := range line {
+		if r == '\t' {
+			spaces := width - col%width
+			out.WriteString(strings.Repeat(" ", spaces))
+			col += spaces
+			continue
+		}
+		out.WriteRune(r)
+		col++
+	}
+	return out.String()
+}
+
+func unexpandTabs(line string, width int) string {
+	var out strings.Builder
+	col, spaces := 0, 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			spaces++
+			col++
+			if col%width == 0 {
+				if spaces > 1 {
+					out.WriteByte('\t')
+				} else {
+					out.WriteByte(' ')
+				}
+				spaces = 0
+			}
+		case '\t':
+			// Pending spaces are covered by the tab.
+			out.WriteByte('\t')
+			col += width - col%width
+			spaces = 0
+		default:
+			out.WriteString(strings.Repeat(" ", spaces))
+			out.WriteRune(r)
+			col++
+			spaces = 0
+		}
+	}
+	out.WriteString(strings.Repeat(" ", spaces))
+	return out.String()
+}
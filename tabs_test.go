@@ -0,0 +1,70 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandTabs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{name: "leading", in: "\tx", width: 4, want: "    x"},
+		{name: "to next stop", in: "ab\tc\td", width: 4, want: "ab  c   d"},
+		{name: "at stop", in: "abcd\te", width: 4, want: "abcd    e"},
+		{name: "resets each line", in: "abc\td\n\te", width: 4, want: "abc d\n    e"},
+		{name: "runes", in: "äö\tx", width: 4, want: "äö  x"},
+		{name: "zero width", in: "a\tb", width: 0, want: "a\tb"},
+		{name: "negative width", in: "a\tb", width: -1, want: "a\tb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Echo(tt.in).ExpandTabs(tt.width).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want+"\n", got)
+		})
+	}
+}
+
+func TestUnexpandTabs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{name: "leading", in: "    x", width: 4, want: "\tx"},
+		{name: "inside line", in: "ab  c   d", width: 4, want: "ab\tc\td"},
+		{name: "single space kept", in: "abc d", width: 4, want: "abc d"},
+		{name: "not reaching stop", in: "a  b", width: 4, want: "a  b"},
+		{name: "trailing spaces", in: "a  ", width: 4, want: "a  "},
+		{name: "spaces before tab", in: "a \tb", width: 4, want: "a\tb"},
+		{name: "resets each line", in: "abc d\n    e", width: 4, want: "abc d\n\te"},
+		{name: "zero width", in: "    x", width: 0, want: "    x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Echo(tt.in).UnexpandTabs(tt.width).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want+"\n", got)
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		in := "\tfoo:\tbar\n\t\tbaz\tqux"
+		got, err := Echo(in).ExpandTabs(8).UnexpandTabs(8).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, in+"\n", got)
+	})
+}
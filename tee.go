@@ -0,0 +1,189 @@
+package script
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Tee passes the stream through unchanged, while also writing it to the given writer. If writing
+// to the writer fails, it will result in an error in the output, and the writer will not be
+// written to anymore, but the stream will still pass through.
+//
+// Shell command: `tee`.
+func (s Stream) Tee(w io.Writer) Stream {
+	return s.Through(tee{w: w})
+}
+
+type tee struct {
+	w io.Writer
+}
+
+func (t tee) Pipe(stdin io.Reader) (io.Reader, error) {
+	return &teeReader{r: stdin, w: t.w}, nil
+}
+
+func (t tee) Name() string {
+	return "tee"
+}
+
+type teeReader struct {
+	r io.Reader
+	w io.Writer
+	// err is the error that occurred while writing to w.
+	err error
+}
+
+func (t *teeReader) Read(b []byte) (int, error) {
+	n, err := t.r.Read(b)
+	if n > 0 && t.err == nil {
+		if _, err := t.w.Write(b[:n]); err != nil {
+			t.err = fmt.Errorf("tee write: %w", err)
+		}
+	}
+	return n, err
+}
+
+func (t *teeReader) Close() error {
+	return t.err
+}
+
+// Split duplicates the stream into two streams that can be read independently, such that each of
+// them outputs the whole content of the stream. The streams can be read one after the other, or
+// concurrently. The stream is read only once, and it is closed once it was fully read, or once both
+// returned streams were closed.
+//
+// Content that was read by one of the streams and was not read yet by the other is stored in
+// memory. When the streams are read at very different rates, for example one after the other, up
+// to the whole content of the stream is stored in memory.
+//
+// The errors of the stream are part of the errors of both returned streams. Errors that occur when
+// the stream is closed are returned by both of them, unless one of the streams was closed before
+// the stream was fully read.
+//
+// Shell command: `tee >(<command1>) | <command2>`.
+func (s Stream) Split() (Stream, Stream) {
+	src := &sharedSource{s: s}
+	return src.branch("split(0)"), src.branch("split(1)")
+}
+
+// sharedSource reads a stream for several branches, and stores the content that was not read yet
+// by all of them.
+type sharedSource struct {
+	mu sync.Mutex
+	s  Stream
+	// retain keeps all the content of the stream, such that branches can be added at any time.
+	// Otherwise, the stream is closed once all the branches were closed.
+	retain bool
+	// buf holds the content from offset start of the stream, that was not read by all the
+	// branches that are not closed.
+	buf   []byte
+	start int
+	// offsets are the offsets in the stream that each branch read until.
+	offsets []int
+	closed  []bool
+	// err is the error of reading the stream, which is io.EOF once it was fully read.
+	err error
+	// done indicates that the stream was closed, and closeErr is the error of closing it.
+	done     bool
+	closeErr error
+}
+
+// branch returns a new stream that reads the content of the source from its beginning. The errors
+// of the stages of the source stream are part of the errors of the branch.
+func (p *sharedSource) branch(stage string) Stream {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b := &sourceBranch{src: p, i: len(p.offsets)}
+	p.offsets = append(p.offsets, p.start)
+	p.closed = append(p.closed, false)
+	return Stream{stage: stage, r: b, err: p.s.Error(), ctx: p.s.ctx}
+}
+
+func (p *sharedSource) read(i int, b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for p.offsets[i] == p.start+len(p.buf) {
+		if p.err != nil {
+			return 0, p.err
+		}
+		chunk := make([]byte, len(b))
+		n, err := p.s.r.Read(chunk)
+		p.buf = append(p.buf, chunk[:n]...)
+		if err != nil {
+			p.err = err
+			p.closeSource()
+		}
+		if len(b) == 0 {
+			return 0, p.err
+		}
+	}
+	n := copy(b, p.buf[p.offsets[i]-p.start:])
+	p.offsets[i] += n
+	p.trim()
+	return n, nil
+}
+
+// trim drops the content that all the branches that are not closed already read.
+func (p *sharedSource) trim() {
+	if p.retain {
+		return
+	}
+	min := p.start + len(p.buf)
+	for i, offset := range p.offsets {
+		if !p.closed[i] && offset < min {
+			min = offset
+		}
+	}
+	p.buf = p.buf[min-p.start:]
+	p.start = min
+}
+
+func (p *sharedSource) close(i int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed[i] = true
+	if !p.retain && p.allClosed() {
+		p.closeSource()
+		p.buf = nil
+	} else {
+		p.trim()
+	}
+	if !p.done {
+		return nil
+	}
+	return p.closeErr
+}
+
+func (p *sharedSource) allClosed() bool {
+	for _, closed := range p.closed {
+		if !closed {
+			return false
+		}
+	}
+	return true
+}
+
+// closeSource closes the readers of all the stages of the stream. The errors of the stages are not
+// part of closeErr, since they are already part of the errors of all the branches.
+func (p *sharedSource) closeSource() {
+	if p.done {
+		return
+	}
+	p.done = true
+	p.closeErr = p.s.closeReaders()
+}
+
+// sourceBranch is a reader of one of the branches of a shared source.
+type sourceBranch struct {
+	src *sharedSource
+	i   int
+}
+
+func (b *sourceBranch) Read(p []byte) (int, error) {
+	return b.src.read(b.i, p)
+}
+
+func (b *sourceBranch) Close() error {
+	return b.src.close(b.i)
+}
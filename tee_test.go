@@ -0,0 +1,124 @@
+package script
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tee", func(t *testing.T) {
+		var tee bytes.Buffer
+		got, err := Echo("a\nb\nab").Tee(&tee).Grep("a").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\nab\n", got)
+		assert.Equal(t, "a\nb\nab\n", tee.String())
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		got, err := Echo("a\nb").Tee(failWriter{}).ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "a\nb\n", got)
+	})
+}
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) { return 0, errors.New("failed") }
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat("line\n", 10000)
+	wantSum := sha256.Sum256([]byte(content))
+
+	t.Run("one after the other", func(t *testing.T) {
+		r := &closeCounter{Reader: strings.NewReader(content)}
+		lines, sum := FromReader(r).Split()
+		n, err := lines.CountLines()
+		require.NoError(t, err)
+		assert.Equal(t, 10000, n)
+		got, err := sum.SHA256Sum()
+		require.NoError(t, err)
+		assert.Equal(t, hex.EncodeToString(wantSum[:]), got)
+		assert.Equal(t, 1, r.closed)
+	})
+
+	t.Run("concurrently", func(t *testing.T) {
+		r := &closeCounter{Reader: iotest.HalfReader(strings.NewReader(content))}
+		a, b := FromReader(r).Split()
+		var (
+			wg         sync.WaitGroup
+			gotA, gotB string
+			errA, errB error
+		)
+		wg.Add(2)
+		go func() { defer wg.Done(); gotA, errA = a.ToString() }()
+		go func() { defer wg.Done(); gotB, errB = b.ToString() }()
+		wg.Wait()
+		require.NoError(t, errA)
+		require.NoError(t, errB)
+		assert.Equal(t, content, gotA)
+		assert.Equal(t, content, gotB)
+		assert.Equal(t, 1, r.closed)
+	})
+
+	t.Run("transformed branches", func(t *testing.T) {
+		a, b := Echo("a\nbb\nccc").Split()
+		got, err := a.Grep("b").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "bb\n", got)
+		got, err = b.Head(1).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\n", got)
+	})
+
+	t.Run("close before done", func(t *testing.T) {
+		r := &closeCounter{Reader: strings.NewReader(content)}
+		a, b := FromReader(r).Split()
+		require.NoError(t, a.Close())
+		assert.Equal(t, 0, r.closed)
+		got, err := b.ToString()
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+		assert.Equal(t, 1, r.closed)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		s := Cat("testdata/a.txt", "no-such-file")
+		a, b := Ls("no-such-dir").Stream.Split()
+		assert.Error(t, a.Error())
+		assert.Error(t, b.Error())
+
+		a, b = s.Split()
+		got, err := a.ToString()
+		assert.True(t, errors.Is(err, os.ErrNotExist), "%v", err)
+		assert.Equal(t, "a\n", got)
+		got, err = b.ToString()
+		assert.True(t, errors.Is(err, os.ErrNotExist), "%v", err)
+		assert.Equal(t, "a\n", got)
+	})
+}
+
+// closeCounter is a reader that counts the times it was closed.
+type closeCounter struct {
+	io.Reader
+	closed int
+}
+
+func (c *closeCounter) Close() error {
+	c.closed++
+	return nil
+}
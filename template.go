@@ -0,0 +1,65 @@
+package script
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Template renders each line with the given `text/template`, and outputs the rendered result as a
+// line. In the template, `{{.}}` is the line, `{{.Line}}` is also the line and `{{.Fields}}` are the
+// white space separated fields of the line, for example `{{index .Fields 1}}`.
+//
+// A template that fails to be parsed results in an error and an empty stream. If the template
+// fails to be executed for a line, it will result in an error in the output, and the line will be
+// omitted.
+func (s Stream) Template(tmpl string) Stream {
+	t, err := template.New("line").Parse(tmpl)
+	if err != nil {
+		return s.failed("template", fmt.Errorf("parse template: %w", err))
+	}
+	return s.Modify(&lineTemplate{tmpl: t})
+}
+
+// TemplateLine is the data that is available for a template in the `Template` method.
+type TemplateLine struct {
+	// Line is the line, without the trailing new line.
+	Line string
+	// Fields are the white space separated fields of the line.
+	Fields []string
+}
+
+// String returns the line, such that `{{.}}` in a template renders the line.
+func (l TemplateLine) String() string {
+	return l.Line
+}
+
+type lineTemplate struct {
+	tmpl   *template.Template
+	errors *multierror.Error
+}
+
+func (t *lineTemplate) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	data := TemplateLine{Line: string(line), Fields: strings.Fields(string(line))}
+	var out bytes.Buffer
+	if err := t.tmpl.Execute(&out, data); err != nil {
+		t.errors = multierror.Append(t.errors, fmt.Errorf("line %q: %w", line, err))
+		return nil, nil
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+func (t *lineTemplate) Close() error {
+	return t.errors.ErrorOrNil()
+}
+
+func (t *lineTemplate) Name() string {
+	return fmt.Sprintf("template(%s)", t.tmpl.Root)
+}
@@ -0,0 +1,39 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("line", func(t *testing.T) {
+		got, err := Echo("a.go\nb.go").Template("// {{.}}").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "// a.go\n// b.go\n", got)
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		got, err := Echo("a 1\nb  2").Template("{{index .Fields 1}}={{.Line}}").ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "1=a 1\n2=b  2\n", got)
+	})
+
+	t.Run("execute error", func(t *testing.T) {
+		got, err := Echo("a 1\nb\nc 3").Template("{{index .Fields 1}}").ToString()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `line "b"`)
+		assert.Equal(t, "1\n3\n", got)
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		s := Echo("a").Template("{{")
+		assert.Error(t, s.Error())
+		got, err := s.ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "", got)
+	})
+}
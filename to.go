@@ -6,20 +6,42 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/go-multierror"
 )
 
 // To writes the output of the stream to an io.Writer and closes it.
 func (s Stream) To(w io.Writer) error {
+	_, err := s.to(w)
+	return err
+}
+
+// to writes the output of the stream to an io.Writer, closes it and returns the number of written
+// bytes.
+func (s Stream) to(w io.Writer) (int64, error) {
 	var errors *multierror.Error
-	if _, err := io.Copy(w, s); err != nil {
+	// The reader is wrapped to prevent io.Copy from calling the stream's WriteTo method.
+	n, err := io.Copy(w, struct{ io.Reader }{s.r})
+	if err != nil {
 		errors = multierror.Append(errors, err)
 	}
 	if err := s.Close(); err != nil {
 		errors = multierror.Append(errors, err)
 	}
-	return errors.ErrorOrNil()
+	return n, errors.ErrorOrNil()
+}
+
+// WriteTo writes the output of the stream to an io.Writer, closes it and returns the number of
+// written bytes, together with the errors that occurred in the stream. It implements the
+// `io.WriterTo` interface, such that `io.Copy` from a stream closes it and returns its errors.
+//
+// Since WriteTo closes the stream, any copying from a stream that uses `io.WriterTo`, such as
+// `io.Copy`, closes it as a side effect, and the stream should not be closed again. A stream that
+// should be copied without being closed can be wrapped in a type that only implements
+// `io.Reader`.
+func (s Stream) WriteTo(w io.Writer) (int64, error) {
+	return s.to(w)
 }
 
 func (s Stream) Iterate(iterator func(line []byte) error) error {
@@ -36,6 +58,13 @@ func (s Stream) ToStdout() error {
 	return s.To(os.Stdout)
 }
 
+// Stdout pipes the stdout of the stream to screen and returns the number of written bytes. The
+// output that was produced is written even if the stream failed.
+func (s Stream) Stdout() (int, error) {
+	n, err := s.to(os.Stdout)
+	return int(n), err
+}
+
 // ToString reads stdout of the stream and returns it as a string.
 func (s Stream) ToString() (string, error) {
 	var out bytes.Buffer
@@ -44,6 +73,28 @@ func (s Stream) ToString() (string, error) {
 
 }
 
+// String reads stdout of the stream and returns it as a string.
+func (s Stream) String() (string, error) {
+	return s.ToString()
+}
+
+// Bytes reads stdout of the stream and returns it as a byte slice.
+func (s Stream) Bytes() ([]byte, error) {
+	var out bytes.Buffer
+	err := s.To(&out)
+	return out.Bytes(), err
+}
+
+// Slice reads stdout of the stream and returns its lines as a slice of strings. The trailing
+// newline of the last line does not result in an empty last element.
+func (s Stream) Slice() ([]string, error) {
+	out, err := s.ToString()
+	if out == "" {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(out, "\n"), "\n"), err
+}
+
 // ToFile dumps the output of the stream to a file.
 func (s Stream) ToFile(path string) error {
 	f, err := File(path)
@@ -54,14 +105,79 @@ func (s Stream) ToFile(path string) error {
 	return s.To(f)
 }
 
-// AppendFile appends the output of the stream to a file.
-func (s Stream) AppendFile(path string) error {
-	f, err := AppendFile(path)
+// WriteFile dumps the output of the stream to a file and returns the number of written bytes. The
+// file is created if it does not exist and truncated otherwise.
+func (s Stream) WriteFile(path string) (int, error) {
+	return s.toFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+}
+
+// AppendFile appends the output of the stream to a file and returns the number of written bytes.
+// The file is created if it does not exist.
+func (s Stream) AppendFile(path string) (int, error) {
+	return s.toFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND)
+}
+
+// WriteFileAtomic writes the output of the stream to a file and returns the number of written
+// bytes, such that readers of the file never see a partially written file. The output is written to
+// a temporary file in the same directory, which is renamed to the given path only if the stream
+// and the write succeeded. Otherwise, the temporary file is removed and an existing file is left
+// unchanged. The mode of an existing file is kept, and a new file is created with mode 0644.
+func (s Stream) WriteFileAtomic(path string) (int, error) {
+	if err := makeDir(path); err != nil {
+		s.Close()
+		return 0, err
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
 	if err != nil {
-		return err
+		s.Close()
+		return 0, pathError("create file", path, err)
 	}
-	defer f.Close()
-	return s.To(f)
+
+	var errors *multierror.Error
+	n, err := s.to(f)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	if err := f.Chmod(mode); err != nil {
+		errors = multierror.Append(errors, pathError("chmod file", path, err))
+	}
+	if err := f.Sync(); err != nil {
+		errors = multierror.Append(errors, pathError("sync file", path, err))
+	}
+	if err := f.Close(); err != nil {
+		errors = multierror.Append(errors, pathError("close file", path, err))
+	}
+	if errors == nil {
+		if err := os.Rename(f.Name(), path); err != nil {
+			errors = multierror.Append(errors, pathError("rename file", path, err))
+		}
+	}
+	if errors != nil {
+		os.Remove(f.Name())
+	}
+	return int(n), errors.ErrorOrNil()
+}
+
+// toFile opens a file with the given flags and writes the output of the stream to it. The file is
+// closed also if the stream failed, while keeping the data that was written. If the file can't be
+// opened, the stream is closed and its errors are returned together with the open error.
+func (s Stream) toFile(path string, flag int) (int, error) {
+	if err := makeDir(path); err != nil {
+		return 0, multierror.Append(err, s.Close())
+	}
+	f, err := os.OpenFile(path, flag, 0644)
+	if err != nil {
+		return 0, multierror.Append(pathError("open path", path, err), s.Close())
+	}
+	n, err := s.to(f)
+	if closeErr := f.Close(); closeErr != nil {
+		err = multierror.Append(err, pathError("close file", path, closeErr))
+	}
+	return int(n), err
 }
 
 // ToTempFile dumps the output of the stream to a temporary file and returns the temporary files'
@@ -80,6 +196,15 @@ func (s Stream) Discard() error {
 	return s.To(ioutil.Discard)
 }
 
+// Drain reads the whole stream and discards the output, and returns the errors that occurred in
+// the stream. It is useful for streams that are executed for their side effects, such as `Tee` or
+// `ExecForEach`. It is the same as `Discard`.
+//
+// Shell command: `> /dev/null`.
+func (s Stream) Drain() error {
+	return s.Discard()
+}
+
 func File(path string) (io.WriteCloser, error) {
 	err := makeDir(path)
 	if err != nil {
@@ -100,5 +225,9 @@ func AppendFile(path string) (io.WriteCloser, error) {
 }
 
 func makeDir(path string) error {
-	return os.MkdirAll(filepath.Dir(path), 0775)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0775); err != nil {
+		return pathError("create dir", dir, err)
+	}
+	return nil
 }
@@ -1,11 +1,16 @@
 package script
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -36,12 +41,14 @@ func TestAppendFile(t *testing.T) {
 
 	path := filepath.Join(dir, "file")
 
-	err = Echo("hello world").AppendFile(path)
+	n, err := Echo("hello world").AppendFile(path)
 	require.NoError(t, err)
 	defer os.Remove(path)
+	assert.Equal(t, 12, n)
 
-	err = Echo("hello world").AppendFile(path)
+	n, err = Echo("hello world").AppendFile(path)
 	require.NoError(t, err)
+	assert.Equal(t, 12, n)
 
 	got, err := Cat(path).ToString()
 	require.NoError(t, err)
@@ -49,6 +56,133 @@ func TestAppendFile(t *testing.T) {
 	assert.Equal(t, "hello world\nhello world\n", got)
 }
 
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "file")
+
+	n, err := Echo("hello world").WriteFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 12, n)
+
+	// Writing again should truncate the file.
+	n, err = Echo("hello").WriteFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 6, n)
+
+	got, err := Cat(path).ToString()
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", got)
+}
+
+func TestWriteFile_streamError(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "file")
+
+	n, err := Cat("testdata/a.txt", "no-such-file").WriteFile(path)
+	assert.Error(t, err)
+	assert.Equal(t, 2, n)
+
+	// Data that was produced was written to the file.
+	got, err := Cat(path).ToString()
+	require.NoError(t, err)
+	assert.Equal(t, "a\n", got)
+}
+
+func TestWriteFile_openError(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "file")
+	require.NoError(t, ioutil.WriteFile(file, nil, 0664))
+
+	for _, path := range []string{
+		// The directory of the path is a file.
+		filepath.Join(file, "file"),
+		// The path is a directory.
+		dir,
+	} {
+		r := &closeRecorder{Reader: strings.NewReader("a\n")}
+		s := From("a", r).ApplyErr(func(r io.Reader) (io.Reader, error) { return r, errors.New("stage") })
+		_, err := s.WriteFile(path)
+		var errs *multierror.Error
+		require.True(t, errors.As(err, &errs))
+		assert.Len(t, errs.Errors, 2, "open error and stream error: %v", err)
+		assert.True(t, r.closed)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "script")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("new file", func(t *testing.T) {
+		path := filepath.Join(dir, "new", "file")
+		n, err := Echo("hello world").WriteFileAtomic(path)
+		require.NoError(t, err)
+		assert.Equal(t, 12, n)
+
+		got, err := Cat(path).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "hello world\n", got)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	})
+
+	t.Run("replace keeps mode", func(t *testing.T) {
+		path := filepath.Join(dir, "replace")
+		require.NoError(t, ioutil.WriteFile(path, []byte("old content\n"), 0600))
+		require.NoError(t, os.Chmod(path, 0600))
+
+		n, err := Echo("new").WriteFileAtomic(path)
+		require.NoError(t, err)
+		assert.Equal(t, 4, n)
+
+		got, err := Cat(path).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "new\n", got)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("stream error", func(t *testing.T) {
+		path := filepath.Join(dir, "failed")
+		require.NoError(t, ioutil.WriteFile(path, []byte("original\n"), 0644))
+
+		n, err := Cat("testdata/a.txt", "no-such-file").WriteFileAtomic(path)
+		assert.Error(t, err)
+		assert.Equal(t, 2, n)
+
+		// The original file was not changed.
+		got, err := Cat(path).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "original\n", got)
+	})
+
+	// No temporary files are left in the directory.
+	t.Run("no temporary files", func(t *testing.T) {
+		got, err := Ls(dir).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"failed", "new", "replace"}), got)
+	})
+}
+
 func TestToTempFile(t *testing.T) {
 	t.Parallel()
 
@@ -71,3 +205,121 @@ func TestIterate(t *testing.T) {
 	})
 	assert.Equal(t, out, []byte("abc"))
 }
+
+func TestStdout(t *testing.T) {
+	// Create a temporary file to fake stdout.
+	fakeStdout, err := ioutil.TempFile("", "")
+	require.NoError(t, err)
+	defer os.Remove(fakeStdout.Name())
+	defer fakeStdout.Close()
+
+	// Temporarely replace stdout with the temporary file.
+	stdout := os.Stdout
+	os.Stdout = fakeStdout
+	defer func() { os.Stdout = stdout }()
+
+	n, err := Ls("no-such-file", "testdata").Stdout()
+	assert.Error(t, err)
+	assert.Equal(t, 30, n)
+
+	got, err := Cat(fakeStdout.Name()).ToString()
+	require.NoError(t, err)
+	assert.Equal(t, "testdata/a.txt\ntestdata/b.txt\n", got)
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	got, err := Echo("hello world").String()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world\n", got)
+}
+
+func TestDrain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("side effects", func(t *testing.T) {
+		var out bytes.Buffer
+		r := &closeRecorder{Reader: strings.NewReader("a\nb\n")}
+		require.NoError(t, FromReader(r).Tee(&out).Drain())
+		assert.Equal(t, "a\nb\n", out.String())
+		assert.True(t, r.closed)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Cat("testdata/a.txt", "missing", "testdata/b.txt").Tee(&out).Drain()
+		assert.Error(t, err)
+		assert.Equal(t, "a\nbb\n", out.String())
+	})
+}
+
+func TestWriteTo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("write to", func(t *testing.T) {
+		var out bytes.Buffer
+		n, err := Echo("hello world").WriteTo(&out)
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), n)
+		assert.Equal(t, "hello world\n", out.String())
+	})
+
+	t.Run("io.Copy", func(t *testing.T) {
+		var out bytes.Buffer
+		n, err := io.Copy(&out, Cat("testdata/a.txt", "no-such-file"))
+		assert.Error(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, "a\n", out.String())
+	})
+
+	t.Run("io.Copy to file", func(t *testing.T) {
+		// A file implements io.ReaderFrom, which should not recurse into the stream's WriteTo.
+		f, err := ioutil.TempFile("", "script")
+		require.NoError(t, err)
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		_, err = io.Copy(f, Echo("hello"))
+		require.NoError(t, err)
+		got, err := ioutil.ReadFile(f.Name())
+		require.NoError(t, err)
+		assert.Equal(t, "hello\n", string(got))
+	})
+}
+
+func TestBytes(t *testing.T) {
+	t.Parallel()
+
+	got, err := Echo("hello world").Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello world\n"), got)
+}
+
+func TestSlice(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lines", func(t *testing.T) {
+		got, err := Echo("a\n\nb").Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "", "b"}, got)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		got, err := Ls("testdata").Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"testdata/a.txt", "testdata/b.txt"}, got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := From("empty", strings.NewReader("")).Slice()
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		got, err := Ls("no-such-file", "testdata/a.txt").Slice()
+		assert.Error(t, err)
+		assert.Equal(t, []string{"testdata/a.txt"}, got)
+	})
+}
@@ -0,0 +1,54 @@
+package script
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Touch creates empty files in the given paths if they do not exist, and updates the access and
+// modification times of the files to the current time. It returns the touched files.
+//
+// If any of the paths fails to be touched, it will result in an error in the output, but the
+// stream will still contain all the files that were successfully touched.
+//
+// Shell command: `touch <paths>`.
+func Touch(paths ...string) Files {
+	return TouchAt(time.Now(), paths...)
+}
+
+// TouchAt touches the given paths, similar to `Touch`, but updates the access and modification
+// times of the files to the given time.
+//
+// Shell command: `touch -d <t> <paths>`.
+func TouchAt(t time.Time, paths ...string) Files {
+	var (
+		files  []FileInfo
+		errors *multierror.Error
+	)
+	for _, path := range paths {
+		info, err := touch(path, t)
+		if err != nil {
+			errors = multierror.Append(errors, pathError("touch", path, err))
+			continue
+		}
+		files = append(files, FileInfo{FileInfo: info, Path: path})
+	}
+	return newFiles(fmt.Sprintf("touch(%+v)", paths), files, errors.ErrorOrNil())
+}
+
+func touch(path string, t time.Time) (os.FileInfo, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0664)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Chtimes(path, t, t); err != nil {
+		return nil, err
+	}
+	return os.Stat(path)
+}
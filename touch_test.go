@@ -0,0 +1,52 @@
+package script
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTouch(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+
+	t.Run("touch", func(t *testing.T) {
+		existing := filepath.Join(dir, "a.txt")
+		old := time.Now().Add(-time.Hour)
+		require.NoError(t, os.Chtimes(existing, old, old))
+		created := filepath.Join(dir, "new")
+
+		files := Touch(existing, created)
+		got, err := files.Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{existing, created}, got)
+		require.Len(t, files.Files, 2)
+		assert.True(t, files.Files[0].ModTime().After(old))
+		assert.Equal(t, int64(0), files.Files[1].Size())
+
+		content, err := ioutil.ReadFile(existing)
+		require.NoError(t, err)
+		assert.Equal(t, existing+"\n", string(content))
+	})
+
+	t.Run("touch at", func(t *testing.T) {
+		at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		files := TouchAt(at, filepath.Join(dir, "b", "c.txt"))
+		require.NoError(t, files.Error())
+		require.Len(t, files.Files, 1)
+		assert.True(t, files.Files[0].ModTime().Equal(at))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		got, err := Touch(filepath.Join(dir, "no-such-dir", "x"), filepath.Join(dir, "y")).Slice()
+		assert.Error(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "y")}, got)
+	})
+}
@@ -0,0 +1,100 @@
+package script
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Tree returns a stream with the files rendered as a tree, in the format of the `tree` command. The
+// first line is the longest directory that contains all the files, and the files are shown under
+// their parent directories, which are derived from the file paths. This makes it suitable for the
+// output of `LsRecursive`, that lists only files, as well as for listed directories, which are
+// shown without children if they contain no listed files. Entries are ordered by their first
+// appearance in the list.
+//
+// Shell command: `tree`.
+func (f Files) Tree() Stream {
+	if len(f.Files) == 0 {
+		return f.stream("tree", strings.NewReader(""))
+	}
+
+	paths := make([][]string, len(f.Files))
+	for i, file := range f.Files {
+		paths[i] = strings.Split(filepath.ToSlash(filepath.Clean(file.Path)), "/")
+	}
+	prefix := commonDir(paths)
+
+	root := &treeNode{}
+	for _, path := range paths {
+		root.add(path[len(prefix):])
+	}
+
+	var out strings.Builder
+	out.WriteString(treeRoot(prefix))
+	out.WriteByte('\n')
+	root.write(&out, "")
+	return f.stream("tree", strings.NewReader(out.String()))
+}
+
+// commonDir returns the longest list of leading directory components that is shared by the given
+// split paths. The last component of each path is not considered as a directory.
+func commonDir(paths [][]string) []string {
+	prefix := paths[0][:len(paths[0])-1]
+	for _, path := range paths[1:] {
+		dir := path[:len(path)-1]
+		n := 0
+		for n < len(prefix) && n < len(dir) && prefix[n] == dir[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return prefix
+}
+
+// treeRoot returns the name of the root directory given its split components.
+func treeRoot(prefix []string) string {
+	root := strings.Join(prefix, "/")
+	switch {
+	case len(prefix) == 1 && prefix[0] == "":
+		return "/"
+	case root == "":
+		return "."
+	}
+	return filepath.FromSlash(root)
+}
+
+type treeNode struct {
+	name     string
+	children []*treeNode
+}
+
+// add adds the given split path under the node.
+func (n *treeNode) add(path []string) {
+	if len(path) == 0 {
+		return
+	}
+	var child *treeNode
+	for _, c := range n.children {
+		if c.name == path[0] {
+			child = c
+			break
+		}
+	}
+	if child == nil {
+		child = &treeNode{name: path[0]}
+		n.children = append(n.children, child)
+	}
+	child.add(path[1:])
+}
+
+// write writes the children of the node, each line starts with the given indentation.
+func (n *treeNode) write(out *strings.Builder, indent string) {
+	for i, child := range n.children {
+		branch, childIndent := "├── ", "│   "
+		if i == len(n.children)-1 {
+			branch, childIndent = "└── ", "    "
+		}
+		out.WriteString(indent + branch + child.name + "\n")
+		child.write(out, indent+childIndent)
+	}
+}
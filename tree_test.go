@@ -0,0 +1,67 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesTree(t *testing.T) {
+	t.Parallel()
+
+	dir := testTree(t)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "empty"), 0775))
+
+	t.Run("recursive", func(t *testing.T) {
+		got, err := LsRecursive(dir).Tree().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, dir+`
+├── a.txt
+└── b
+    ├── c.txt
+    └── d
+        └── e.txt
+`, got)
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		got, err := Ls(dir).Tree().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, dir+`
+├── a.txt
+├── b
+└── empty
+`, got)
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		got, err := Ls("testdata/a.txt").Tree().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "testdata\n└── a.txt\n", got)
+	})
+
+	t.Run("relative", func(t *testing.T) {
+		got, err := Ls("testdata/a.txt", "tree.go").Tree().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, ".\n├── testdata\n│   └── a.txt\n└── tree.go\n", got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := Ls("no-such-file").Tree().ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "", got)
+	})
+}
+
+func TestTreeRoot(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/", treeRoot([]string{""}))
+	assert.Equal(t, ".", treeRoot(nil))
+	assert.Equal(t, "/tmp", treeRoot([]string{"", "tmp"}))
+	assert.Equal(t, filepath.FromSlash("a/b"), treeRoot([]string{"a", "b"}))
+}
@@ -0,0 +1,54 @@
+package script
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Trim removes leading and trailing white spaces from each line.
+func (s Stream) Trim() Stream {
+	return s.Modify(mapLines{name: "trim", fn: bytes.TrimSpace})
+}
+
+// TrimPrefix removes the given prefix from each line that starts with it.
+func (s Stream) TrimPrefix(prefix string) Stream {
+	return s.Modify(mapLines{
+		name: fmt.Sprintf("trim-prefix(%q)", prefix),
+		fn:   func(line []byte) []byte { return bytes.TrimPrefix(line, []byte(prefix)) },
+	})
+}
+
+// TrimSuffix removes the given suffix from each line that ends with it.
+func (s Stream) TrimSuffix(suffix string) Stream {
+	return s.Modify(mapLines{
+		name: fmt.Sprintf("trim-suffix(%q)", suffix),
+		fn:   func(line []byte) []byte { return bytes.TrimSuffix(line, []byte(suffix)) },
+	})
+}
+
+// DropBlank removes lines that are empty or contain only white spaces.
+//
+// Shell command: `grep -v '^\s*$'`.
+func (s Stream) DropBlank() Stream {
+	return s.Modify(filterLines{
+		name: "drop-blank",
+		keep: func(line []byte) bool { return len(bytes.TrimSpace(line)) > 0 },
+	})
+}
+
+// CompactBlank replaces each run of consecutive lines that are empty or contain only white spaces
+// with the first line of the run.
+//
+// Shell command: `cat -s`.
+func (s Stream) CompactBlank() Stream {
+	prevBlank := false
+	return s.Modify(filterLines{
+		name: "compact-blank",
+		keep: func(line []byte) bool {
+			blank := len(bytes.TrimSpace(line)) == 0
+			keep := !blank || !prevBlank
+			prevBlank = blank
+			return keep
+		},
+	})
+}
@@ -0,0 +1,33 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrim(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    Stream
+		want string
+	}{
+		{name: "trim", s: Echo("  a \n\tb\n  \nc").Trim(), want: "a\nb\n\nc\n"},
+		{name: "trim prefix", s: Echo("./a\n./b\nc./").TrimPrefix("./"), want: "a\nb\nc./\n"},
+		{name: "trim suffix", s: Echo("a.go\nb.go\n.goc").TrimSuffix(".go"), want: "a\nb\n.goc\n"},
+		{name: "drop blank", s: Echo("a\n\n  \t\nb\n").DropBlank(), want: "a\nb\n"},
+		{name: "compact blank", s: Echo("\n\na\n \n\t\n\nb\n\nc\n\n").CompactBlank(), want: "\na\n \nb\n\nc\n\n"},
+		{name: "compact no blank", s: Echo("a\nb").CompactBlank(), want: "a\nb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
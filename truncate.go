@@ -0,0 +1,43 @@
+package script
+
+import "fmt"
+
+// ellipsis is appended to lines that were truncated.
+const ellipsis = '…'
+
+// Truncate shortens each line that is longer than maxLen runes to maxLen runes, where the last rune
+// is replaced with `…`. Lines that are not longer than maxLen are not changed. If maxLen is not
+// positive, the lines are not changed.
+//
+// Shell command: `cut -c 1-<maxLen>`.
+func (s Stream) Truncate(maxLen int) Stream {
+	return s.truncate(fmt.Sprintf("truncate(%d)", maxLen), maxLen, false)
+}
+
+// TruncateLeft shortens each line that is longer than maxLen runes to maxLen runes, similar to
+// `Truncate`, but keeps the end of the line and replaces its beginning with `…`. It is useful for
+// long paths, that their end is usually more interesting.
+//
+// Shell command: `rev | cut -c 1-<maxLen> | rev`.
+func (s Stream) TruncateLeft(maxLen int) Stream {
+	return s.truncate(fmt.Sprintf("truncate-left(%d)", maxLen), maxLen, true)
+}
+
+func (s Stream) truncate(name string, maxLen int, left bool) Stream {
+	if maxLen < 1 {
+		return s
+	}
+	return s.Modify(mapLines{
+		name: name,
+		fn: func(line []byte) []byte {
+			runes := []rune(string(line))
+			if len(runes) <= maxLen {
+				return line
+			}
+			if left {
+				return []byte(string(ellipsis) + string(runes[len(runes)-maxLen+1:]))
+			}
+			return []byte(string(runes[:maxLen-1]) + string(ellipsis))
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		in       string
+		maxLen   int
+		want     string
+		wantLeft string
+	}{
+		{name: "long", in: "abcdef", maxLen: 4, want: "abc…", wantLeft: "…def"},
+		{name: "exact", in: "abcd", maxLen: 4, want: "abcd", wantLeft: "abcd"},
+		{name: "short", in: "ab", maxLen: 4, want: "ab", wantLeft: "ab"},
+		{name: "runes", in: "äöüßéè", maxLen: 3, want: "äö…", wantLeft: "…éè"},
+		{name: "one", in: "abc", maxLen: 1, want: "…", wantLeft: "…"},
+		{name: "zero", in: "abc", maxLen: 0, want: "abc", wantLeft: "abc"},
+		{name: "empty line", in: "", maxLen: 2, want: "", wantLeft: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Echo(tt.in).Truncate(tt.maxLen).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want+"\n", got)
+
+			got, err = Echo(tt.in).TruncateLeft(tt.maxLen).ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLeft+"\n", got)
+		})
+	}
+
+	t.Run("each line", func(t *testing.T) {
+		got, err := Echo("abcdef\nab\n/very/long/path").Truncate(4).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"abc…", "ab", "/ve…"}, got)
+	})
+}
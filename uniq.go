@@ -12,6 +12,35 @@ func (s Stream) Uniq() Stream {
 	return s.Modify(&Uniq{})
 }
 
+// UniqCount omits repeated lines, and prefixes each line with the number of times it was repeated.
+// Only adjacent lines are compared.
+//
+// Shell command: `uniq -c`.
+func (s Stream) UniqCount() Stream {
+	return s.Modify(&Uniq{WriteCount: true})
+}
+
+// Distinct omits lines that already appeared anywhere before in the stream, and outputs the other
+// lines in the order they first appear. Unlike `Uniq`, the lines do not need to be adjacent, so
+// there is no need to sort the stream first. Every distinct line is stored in memory, which is at
+// most the memory that `SortLines().Uniq()` uses, since it stores all the lines. Unlike it, lines
+// are output as soon as they are read, and the output keeps the input order.
+//
+// Shell command: `awk '!seen[$0]++'`.
+func (s Stream) Distinct() Stream {
+	seen := make(map[string]bool)
+	return s.Modify(filterLines{
+		name: "distinct",
+		keep: func(line []byte) bool {
+			if seen[string(line)] {
+				return false
+			}
+			seen[string(line)] = true
+			return true
+		},
+	})
+}
+
 // Uniq report or omit repeated lines.
 //
 // Usage:
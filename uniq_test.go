@@ -22,3 +22,27 @@ func TestUniq_count(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "2\ta\n1\tb\n1\tbb\n1\ta\n", out)
 }
+
+func TestUniqCount(t *testing.T) {
+	t.Parallel()
+
+	out, err := Echo("a\na\nb\nbb\na").UniqCount().ToString()
+	require.NoError(t, err)
+	assert.Equal(t, "2\ta\n1\tb\n1\tbb\n1\ta\n", out)
+}
+
+func TestDistinct(t *testing.T) {
+	t.Parallel()
+
+	t.Run("distinct", func(t *testing.T) {
+		out, err := Echo("b\na\nb\nc\na\n\nb\n").Distinct().ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "b\na\nc\n\n", out)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		out, err := Ls("testdata").Append(Ls("testdata/b.txt", "testdata/a.txt")).Distinct().Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"testdata/a.txt", "testdata/b.txt"}, out)
+	})
+}
@@ -0,0 +1,158 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Watch outputs a line with the path of each file in the given directory when it is created or
+// modified. It never ends, see `WatchWith` for stopping it with a context.
+//
+// Watch does not use file system notifications: it polls the directory, see `WatchWith` for the
+// latency and cost of polling.
+//
+// Shell command: `inotifywait -m -e create,modify --format %w%f <dir>`.
+func Watch(dir string) Stream {
+	return WatchWith(context.Background(), WatchOptions{}, dir)
+}
+
+// WatchOptions are options for watching a directory.
+type WatchOptions struct {
+	// Events adds the event type before each path in the output, separated by a tab. The event
+	// types are "create", "write" and "remove". Removed files are reported only with this option.
+	Events bool
+	// PollInterval is the interval for listing the directory for changes, which is the maximal
+	// latency of reporting a change. If zero, it defaults to 250 milliseconds.
+	PollInterval time.Duration
+}
+
+// WatchWith watches a directory, similar to `Watch`, according to the given options, until the
+// given context is done. When the context is done, the stream ends without an error. The directory
+// is not watched recursively.
+//
+// This is a polling fallback for file system notifications, such as inotify, which are not used.
+// The directory is listed every poll interval, and a file is reported when its modification time
+// or size changed since the previous listing. A change is therefore reported up to a poll interval
+// after it happened, several changes to a file within an interval are reported once, and a change
+// that keeps both the size and the modification time is missed. Each poll reads the directory and
+// the metadata of all of its files, such that the cost grows with the number of files and with a
+// shorter interval.
+func WatchWith(ctx context.Context, opts WatchOptions, dir string) Stream {
+	stage := fmt.Sprintf("watch(%s)", dir)
+	if opts.PollInterval == 0 {
+		opts.PollInterval = 250 * time.Millisecond
+	}
+	state, err := watchScan(dir)
+	if err != nil {
+		return Stream{stage: stage, r: strings.NewReader(""), err: err, ctx: ctx}
+	}
+	return Stream{
+		stage: stage,
+		r:     &watchReader{ctx: ctx, dir: dir, opts: opts, state: state},
+		ctx:   ctx,
+	}
+}
+
+// watchState is the state of a file that is used to detect changes.
+type watchState struct {
+	modTime time.Time
+	size    int64
+}
+
+type watchReader struct {
+	ctx context.Context
+	// stop is the context of a following stage, which also stops watching once it is done.
+	stop  context.Context
+	dir   string
+	opts  WatchOptions
+	state map[string]watchState
+	// partial stores output that was not read yet.
+	partial []byte
+}
+
+func (w *watchReader) Read(b []byte) (int, error) {
+	for len(w.partial) == 0 {
+		select {
+		case <-w.ctx.Done():
+			return 0, io.EOF
+		case <-doneOf(w.stop):
+			return 0, io.EOF
+		case <-time.After(w.opts.PollInterval):
+		}
+		if err := w.update(); err != nil {
+			return 0, err
+		}
+	}
+	var n int
+	w.partial, n = copyBytes(b, w.partial)
+	return n, nil
+}
+
+func (w *watchReader) setContext(ctx context.Context) {
+	w.stop = ctx
+}
+
+// update scans the directory and stores the changes since the last scan in the output.
+func (w *watchReader) update() error {
+	state, err := watchScan(w.dir)
+	if err != nil {
+		return err
+	}
+	var out strings.Builder
+	for _, name := range sortedKeys(state) {
+		prev, ok := w.state[name]
+		switch {
+		case !ok:
+			w.write(&out, "create", name)
+		case prev != state[name]:
+			w.write(&out, "write", name)
+		}
+	}
+	if w.opts.Events {
+		for _, name := range sortedKeys(w.state) {
+			if _, ok := state[name]; !ok {
+				w.write(&out, "remove", name)
+			}
+		}
+	}
+	w.state = state
+	w.partial = []byte(out.String())
+	return nil
+}
+
+func (w *watchReader) write(out *strings.Builder, event, name string) {
+	if w.opts.Events {
+		out.WriteString(event)
+		out.WriteByte('\t')
+	}
+	out.WriteString(filepath.Join(w.dir, name))
+	out.WriteByte('\n')
+}
+
+// watchScan returns the state of all the files in a directory.
+func watchScan(dir string) (map[string]watchState, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, pathError("read dir", dir, err)
+	}
+	state := make(map[string]watchState, len(infos))
+	for _, info := range infos {
+		state[info.Name()] = watchState{modTime: info.ModTime(), size: info.Size()}
+	}
+	return state, nil
+}
+
+func sortedKeys(m map[string]watchState) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
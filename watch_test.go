@@ -0,0 +1,65 @@
+package script
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("watch", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "new.txt"), nil, 0664))
+			time.Sleep(20 * time.Millisecond)
+			appendToFile(t, filepath.Join(dir, "a.txt"), "more")
+		}()
+
+		opts := WatchOptions{PollInterval: 5 * time.Millisecond}
+		got, err := WatchWith(context.Background(), opts, dir).First(2).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, inDir(dir, []string{"new.txt", "a.txt"}), got)
+	})
+
+	t.Run("events", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			require.NoError(t, os.Remove(filepath.Join(dir, "a.txt")))
+		}()
+
+		opts := WatchOptions{Events: true, PollInterval: 5 * time.Millisecond}
+		got, err := WatchWith(context.Background(), opts, dir).First(1).Slice()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"remove\t" + filepath.Join(dir, "a.txt")}, got)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		dir := testTree(t)
+		defer os.RemoveAll(dir)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		got, err := WatchWith(ctx, WatchOptions{PollInterval: time.Millisecond}, dir).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+
+	t.Run("no such dir", func(t *testing.T) {
+		_, err := Watch("no-such-dir").ToString()
+		assert.Error(t, err)
+	})
+}
@@ -2,7 +2,10 @@ package script
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"regexp"
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
@@ -33,7 +36,7 @@ func (s Stream) Wc() Count {
 		count.Words += countWords(scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
-		errors = multierror.Append(errors, fmt.Errorf("scanning stream: %s", err))
+		errors = multierror.Append(errors, fmt.Errorf("scanning stream: %w", err))
 	}
 
 	count.Stream = Stream{
@@ -43,6 +46,115 @@ func (s Stream) Wc() Count {
 	return count
 }
 
+// CountLines counts the number of lines in the stream. A last line without a trailing newline is
+// also counted. The stream is counted as it is read, without storing it.
+//
+// Shell command: `wc -l`.
+func (s Stream) CountLines() (int, error) {
+	var c lineCounter
+	_, err := s.to(&c)
+	return c.count(), err
+}
+
+// CountBytes counts the number of bytes in the stream.
+//
+// Shell command: `wc -c`.
+func (s Stream) CountBytes() (int, error) {
+	n, err := s.to(ioutil.Discard)
+	return int(n), err
+}
+
+// CountWords counts the number of white space separated words in the stream. The stream is
+// counted as it is read, without storing it.
+//
+// Shell command: `wc -w`.
+func (s Stream) CountWords() (int, error) {
+	var (
+		count  int
+		errors *multierror.Error
+	)
+	scanner := bufio.NewScanner(s)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		errors = multierror.Append(errors, fmt.Errorf("scanning stream: %w", err))
+	}
+	if err := s.Close(); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+	return count, errors.ErrorOrNil()
+}
+
+// CountMatches counts the number of lines that contain the given substring. The stream is counted
+// as it is read, without storing it.
+//
+// Shell command: `grep -c -F <substr>`.
+func (s Stream) CountMatches(substr string) (int, error) {
+	return s.countMatches(Grep{Substr: substr})
+}
+
+// CountMatchesRegexp counts the number of lines that match the given regexp. The stream is counted
+// as it is read, without storing it.
+//
+// Shell command: `grep -c <re>`.
+func (s Stream) CountMatchesRegexp(re *regexp.Regexp) (int, error) {
+	return s.countMatches(Grep{Re: re})
+}
+
+// CountOccurrences counts the number of non-overlapping occurrences of the given substring in the
+// stream. Occurrences that span multiple lines are not counted.
+//
+// Shell command: `grep -o -F <substr> | wc -l`.
+func (s Stream) CountOccurrences(substr string) (int, error) {
+	count := 0
+	err := s.Iterate(func(line []byte) error {
+		if line != nil {
+			count += bytes.Count(line, []byte(substr))
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (s Stream) countMatches(g Grep) (int, error) {
+	count := 0
+	err := s.Iterate(func(line []byte) error {
+		if line != nil && g.match(line) {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// lineCounter is a writer that counts the lines written to it.
+type lineCounter struct {
+	lines int
+	// last is the last byte that was written.
+	last byte
+	// written indicates if any byte was written.
+	written bool
+}
+
+func (c *lineCounter) Write(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	c.lines += bytes.Count(b, []byte{'\n'})
+	c.last = b[len(b)-1]
+	c.written = true
+	return len(b), nil
+}
+
+func (c *lineCounter) count() int {
+	if c.written && c.last != '\n' {
+		return c.lines + 1
+	}
+	return c.lines
+}
+
 func (c Count) String() string {
 	return fmt.Sprintf("%d\t%d\t%d\n", c.Lines, c.Words, c.Chars)
 }
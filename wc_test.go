@@ -1,6 +1,8 @@
 package script
 
 import (
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -30,3 +32,83 @@ func TestWc(t *testing.T) {
 		assert.Equal(t, 1, wc.Chars)
 	})
 }
+
+func TestCountLines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    Stream
+		want int
+	}{
+		{name: "lines", s: Echo("a\nb\nc"), want: 3},
+		{name: "no trailing newline", s: From("test", strings.NewReader("a\nb")), want: 2},
+		{name: "empty", s: From("test", strings.NewReader("")), want: 0},
+		{name: "files", s: Ls("testdata").Stream, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.CountLines()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		_, err := Ls("no-such-file").CountLines()
+		assert.Error(t, err)
+	})
+}
+
+func TestCountBytes(t *testing.T) {
+	t.Parallel()
+
+	got, err := Echo("a b\nc").CountBytes()
+	require.NoError(t, err)
+	assert.Equal(t, 6, got)
+
+	_, err = Cat("no-such-file").CountBytes()
+	assert.Error(t, err)
+}
+
+func TestCountWords(t *testing.T) {
+	t.Parallel()
+
+	got, err := Echo("a  b\n\tc d \n").CountWords()
+	require.NoError(t, err)
+	assert.Equal(t, 4, got)
+
+	_, err = Cat("no-such-file").CountWords()
+	assert.Error(t, err)
+}
+
+func TestCountMatches(t *testing.T) {
+	t.Parallel()
+
+	const log = "GET / 200 \nGET /a 500 \nPOST /b 500 500 \nGET /c 404 "
+
+	t.Run("substring", func(t *testing.T) {
+		got, err := Echo(log).CountMatches(" 500 ")
+		require.NoError(t, err)
+		assert.Equal(t, 2, got)
+	})
+
+	t.Run("regexp", func(t *testing.T) {
+		got, err := Echo(log).CountMatchesRegexp(regexp.MustCompile(`^GET`))
+		require.NoError(t, err)
+		assert.Equal(t, 3, got)
+	})
+
+	t.Run("occurrences", func(t *testing.T) {
+		got, err := Echo(log).CountOccurrences("500")
+		require.NoError(t, err)
+		assert.Equal(t, 3, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		got, err := Cat("no-such-file", "testdata/a.txt").CountMatches("a")
+		assert.Error(t, err)
+		assert.Equal(t, 1, got)
+	})
+}
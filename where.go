@@ -0,0 +1,64 @@
+package script
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// FilePredicate reports whether a file should be kept by `Where`. Custom predicates can be used
+// alongside the predicates of this package.
+type FilePredicate func(FileInfo) bool
+
+// Where filters only the files that all the given predicates return true for. If no predicates are
+// given, all the files are kept.
+//
+// Usage:
+//
+//  script.Ls("logs").Where(script.SizeOver(1<<20), script.ModifiedWithin(24*time.Hour))
+//
+// Shell command: `find <path> -maxdepth 0 <predicate1> <predicate2>...`.
+func (f Files) Where(preds ...FilePredicate) Files {
+	return f.filter("where", func(file FileInfo) bool {
+		for _, pred := range preds {
+			if !pred(file) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// SizeOver is a predicate for files that their size is strictly larger than the given number of
+// bytes.
+//
+// Shell command: `find -size +<bytes>c`.
+func SizeOver(bytes int64) FilePredicate {
+	return func(file FileInfo) bool { return file.Size() > bytes }
+}
+
+// ModifiedWithin is a predicate for files that were modified in the given duration before the time
+// that the predicate was created.
+//
+// Shell command: `find -mmin -<d>`.
+func ModifiedWithin(d time.Duration) FilePredicate {
+	after := time.Now().Add(-d)
+	return func(file FileInfo) bool { return file.ModTime().After(after) }
+}
+
+// NameMatches is a predicate for files that their base name matches the given glob pattern, using
+// the `filepath.Match` syntax. An invalid pattern matches no files.
+//
+// Shell command: `find -name <glob>`.
+func NameMatches(glob string) FilePredicate {
+	return func(file FileInfo) bool {
+		ok, _ := filepath.Match(glob, filepath.Base(file.Path))
+		return ok
+	}
+}
+
+// IsRegular is a predicate for regular files.
+//
+// Shell command: `find -type f`.
+func IsRegular() FilePredicate {
+	return func(file FileInfo) bool { return file.Mode().IsRegular() }
+}
@@ -0,0 +1,62 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesWhere(t *testing.T) {
+	t.Parallel()
+
+	dir := testFiles(t)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "d"), 0775))
+
+	tests := []struct {
+		name  string
+		preds []FilePredicate
+		want  []string
+	}{
+		{name: "none", preds: nil, want: []string{"a", "b", "c", "d"}},
+		{name: "size over", preds: []FilePredicate{SizeOver(1), IsRegular()}, want: []string{"a", "b"}},
+		{name: "modified within", preds: []FilePredicate{ModifiedWithin(4*time.Hour + 30*time.Minute)}, want: []string{"a", "c", "d"}},
+		{name: "name matches", preds: []FilePredicate{NameMatches("[bcd]")}, want: []string{"b", "c", "d"}},
+		{name: "is regular", preds: []FilePredicate{IsRegular()}, want: []string{"a", "b", "c"}},
+		{
+			name:  "combined",
+			preds: []FilePredicate{IsRegular(), NameMatches("[bc]"), ModifiedWithin(4*time.Hour + 30*time.Minute)},
+			want:  []string{"c"},
+		},
+		{
+			name:  "custom",
+			preds: []FilePredicate{func(file FileInfo) bool { return file.IsDir() }},
+			want:  []string{"d"},
+		},
+		{name: "invalid glob", preds: []FilePredicate{NameMatches("[")}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := Ls(dir).Where(tt.preds...)
+			got, err := files.Slice()
+			require.NoError(t, err)
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, inDir(dir, tt.want), got)
+			assert.Equal(t, inDir(dir, tt.want), paths(files))
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		got, err := Ls("no-such-file", dir).Where(SizeOver(2), IsRegular()).Slice()
+		assert.Error(t, err)
+		assert.Equal(t, inDir(dir, []string{"a"}), got)
+	})
+}
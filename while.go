@@ -0,0 +1,49 @@
+package script
+
+import "io"
+
+// TakeWhile outputs lines as long as pred returns true for them, and stops reading from the
+// previous stages at the first line for which pred returns false. That line is omitted.
+func (s Stream) TakeWhile(pred func(line string) bool) Stream {
+	return s.Modify(takeWhile(pred))
+}
+
+// DropWhile omits lines as long as pred returns true for them, and outputs the rest of the lines,
+// starting at the first line for which pred returns false.
+func (s Stream) DropWhile(pred func(line string) bool) Stream {
+	return s.Modify(&dropWhile{pred: pred})
+}
+
+type takeWhile func(line string) bool
+
+func (t takeWhile) Modify(line []byte) ([]byte, error) {
+	if line == nil || !t(string(line)) {
+		return nil, io.EOF
+	}
+	return append(line, '\n'), nil
+}
+
+func (t takeWhile) Name() string {
+	return "take-while"
+}
+
+type dropWhile struct {
+	pred func(line string) bool
+	// done indicates that lines are no longer omitted.
+	done bool
+}
+
+func (d *dropWhile) Modify(line []byte) ([]byte, error) {
+	if line == nil {
+		return nil, nil
+	}
+	if !d.done && d.pred(string(line)) {
+		return nil, nil
+	}
+	d.done = true
+	return append(line, '\n'), nil
+}
+
+func (d *dropWhile) Name() string {
+	return "drop-while"
+}
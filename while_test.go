@@ -0,0 +1,42 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakeDropWhile(t *testing.T) {
+	t.Parallel()
+
+	const text = "title: a\nauthor: b\n---\nbody\n---\nend"
+	notMarker := func(line string) bool { return line != "---" }
+
+	t.Run("take while", func(t *testing.T) {
+		got, err := Echo(text).TakeWhile(notMarker).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "title: a\nauthor: b\n", got)
+	})
+
+	t.Run("drop while", func(t *testing.T) {
+		got, err := Echo(text).DropWhile(notMarker).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "---\nbody\n---\nend\n", got)
+	})
+
+	t.Run("take while stops reading", func(t *testing.T) {
+		r := strings.NewReader("a\nb\n" + strings.Repeat("c\n", 100000))
+		got, err := From("test", r).TakeWhile(func(line string) bool { return line != "b" }).ToString()
+		require.NoError(t, err)
+		assert.Equal(t, "a\n", got)
+		assert.True(t, r.Len() > 0)
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		got, err := Cat("no-such-file", "testdata/a.txt").DropWhile(notMarker).ToString()
+		assert.Error(t, err)
+		assert.Equal(t, "", got)
+	})
+}
@@ -0,0 +1,58 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Wrap breaks each line that is longer than width runes into several lines of at most width runes.
+// Lines are broken after the last white space that fits in the width, and the white space is kept
+// at the end of the broken line. Words that are longer than the width are broken in the middle. If
+// width is not positive, the lines are not changed.
+//
+// Shell command: `fold -s -w <width>`.
+func (s Stream) Wrap(width int) Stream {
+	return s.wrap(fmt.Sprintf("wrap(%d)", width), width, true)
+}
+
+// Fold breaks each line that is longer than width runes into several lines of exactly width runes,
+// besides the last one, without considering word boundaries. If width is not positive, the lines
+// are not changed.
+//
+// Shell command: `fold -w <width>`.
+func (s Stream) Fold(width int) Stream {
+	return s.wrap(fmt.Sprintf("fold(%d)", width), width, false)
+}
+
+func (s Stream) wrap(name string, width int, words bool) Stream {
+	if width < 1 {
+		return s
+	}
+	return s.Modify(mapLines{
+		name: name,
+		fn: func(line []byte) []byte {
+			return []byte(strings.Join(wrapLine([]rune(string(line)), width, words), "\n"))
+		},
+	})
+}
+
+// wrapLine breaks the given line into lines of at most width runes. If words is set, the lines are
+// broken after white spaces when possible.
+func wrapLine(line []rune, width int, words bool) []string {
+	var lines []string
+	for len(line) > width {
+		n := width
+		if words {
+			for i := width - 1; i >= 0; i-- {
+				if unicode.IsSpace(line[i]) {
+					n = i + 1
+					break
+				}
+			}
+		}
+		lines = append(lines, string(line[:n]))
+		line = line[n:]
+	}
+	return append(lines, string(line))
+}
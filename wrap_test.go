@@ -0,0 +1,37 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    Stream
+		want string
+	}{
+		{name: "words", s: Echo("the quick brown fox").Wrap(10), want: "the quick \nbrown fox\n"},
+		{name: "long word", s: Echo("abcdefghij klm").Wrap(4), want: "abcd\nefgh\nij \nklm\n"},
+		{name: "short", s: Echo("a b\nc").Wrap(10), want: "a b\nc\n"},
+		{name: "exact", s: Echo("abcd").Wrap(4), want: "abcd\n"},
+		{name: "runes", s: Echo("héllo wörld").Wrap(6), want: "héllo \nwörld\n"},
+		{name: "zero width", s: Echo("a b c").Wrap(0), want: "a b c\n"},
+		{name: "fold", s: Echo("the quick brown fox").Fold(10), want: "the quick \nbrown fox\n"},
+		{name: "fold hard", s: Echo("ab cdef gh").Fold(4), want: "ab c\ndef \ngh\n"},
+		{name: "fold runes", s: Echo("日本語の文").Fold(2), want: "日本\n語の\n文\n"},
+		{name: "fold negative", s: Echo("abc").Fold(-1), want: "abc\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}